@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"math/big"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/fractalplatform/fractal/asset"
@@ -42,6 +44,8 @@ var (
 	acctInfoPrefix      = "acctInfo"
 	accountNameIDPrefix = "accountNameId"
 	counterPrefix       = "accountCounter"
+	acctFrozenPrefix    = "acctFrozen"
+	maxAssetDecimals    = uint64(18)
 	counterID           = uint64(4096)
 )
 
@@ -112,6 +116,8 @@ type UpdateAssetContract struct {
 type AccountManager struct {
 	sdb *state.StateDB
 	ast *asset.Asset
+	// acctCache caches the accounts read during a RecoverTxBatch, it is nil otherwise
+	acctCache map[common.Name]*Account
 }
 
 func SetAccountNameConfig(config *Config) bool {
@@ -204,6 +210,28 @@ func (am *AccountManager) getAccountCounter() (uint64, error) {
 	return accountCounter, nil
 }
 
+// ForEachAccount calls fn for every account in order of creation, it stops at the
+// first error returned by fn.
+func (am *AccountManager) ForEachAccount(fn func(*Account) error) error {
+	accountCounter, err := am.getAccountCounter()
+	if err != nil {
+		return err
+	}
+	for id := counterID + 1; id <= accountCounter; id++ {
+		acct, err := am.GetAccountById(id)
+		if err != nil {
+			return err
+		}
+		if acct == nil {
+			continue
+		}
+		if err := fn(acct); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AccountIsExist check account is exist.
 func (am *AccountManager) AccountIsExist(accountName common.Name) (bool, error) {
 	//check is exist
@@ -477,11 +505,18 @@ func (am *AccountManager) GetAccountByTime(accountName common.Name, time uint64)
 
 //GetAccountByName get account by name
 func (am *AccountManager) GetAccountByName(accountName common.Name) (*Account, error) {
+	if acct, ok := am.acctCache[accountName]; ok {
+		return acct, nil
+	}
 	accountID, err := am.GetAccountIDByName(accountName)
 	if err != nil {
 		return nil, err
 	}
-	return am.GetAccountById(accountID)
+	acct, err := am.GetAccountById(accountID)
+	if err == nil && am.acctCache != nil {
+		am.acctCache[accountName] = acct
+	}
+	return acct, err
 }
 
 //GetAccountIDByName get account id by account name
@@ -503,6 +538,35 @@ func (am *AccountManager) GetAccountIDByName(accountName common.Name) (uint64, e
 	return accountID, nil
 }
 
+// GetAccountProof returns the merkle proof nodes of the account name index and, if
+// the account exists, of the account data. For a missing account the nodes prove
+// its absence.
+func (am *AccountManager) GetAccountProof(accountName common.Name) ([][]byte, error) {
+	proof, err := am.sdb.GetProof(acctManagerName, accountNameIDPrefix+accountName.String())
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := am.GetAccountIDByName(accountName)
+	if err != nil || accountID == 0 {
+		return proof, err
+	}
+	infoProof, err := am.sdb.GetProof(acctManagerName, acctInfoPrefix+strconv.FormatUint(accountID, 10))
+	if err != nil {
+		return nil, err
+	}
+	// both paths start at the state root, keep shared nodes once
+	seen := make(map[string]bool, len(proof))
+	for _, node := range proof {
+		seen[string(node)] = true
+	}
+	for _, node := range infoProof {
+		if !seen[string(node)] {
+			proof = append(proof, node)
+		}
+	}
+	return proof, nil
+}
+
 //GetAccountById get account by account id
 func (am *AccountManager) GetAccountById(id uint64) (*Account, error) {
 	if id == 0 {
@@ -562,6 +626,54 @@ func (am *AccountManager) DeleteAccountByName(accountName common.Name) error {
 	return nil
 }
 
+//DestroyAccount transfer all balances of the account to beneficiary and remove the account,
+//an account with frozen balance or registered as candidate by isCandidate can't be destroyed
+func (am *AccountManager) DestroyAccount(accountName common.Name, beneficiary common.Name, isCandidate func(common.Name) (bool, error)) error {
+	if isCandidate == nil {
+		return fmt.Errorf("destroy account %v without candidate check", accountName)
+	}
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return err
+	}
+	if acct == nil {
+		return ErrAccountNotExist
+	}
+	if acct.IsDestroyed() {
+		return ErrAccountIsDestroy
+	}
+	if accountName == beneficiary {
+		return fmt.Errorf("account %v can't be the beneficiary of itself", accountName)
+	}
+	candidate, err := isCandidate(accountName)
+	if err != nil {
+		return err
+	}
+	if candidate {
+		return fmt.Errorf("account %v is a registered candidate, unregister and refund first", accountName)
+	}
+	for _, ab := range acct.GetBalancesList() {
+		frozen, err := am.getFrozenBalance(acct, ab.AssetID)
+		if err != nil {
+			return err
+		}
+		if frozen.Sign() != 0 {
+			return fmt.Errorf("account %v has frozen balance %v of asset %v", accountName, frozen, ab.AssetID)
+		}
+	}
+	for _, ab := range acct.GetBalancesList() {
+		if err := am.TransferAsset(accountName, beneficiary, ab.AssetID, ab.Balance); err != nil {
+			return err
+		}
+		am.sdb.Delete(acctManagerName, frozenKey(acct, ab.AssetID))
+	}
+
+	am.sdb.Delete(acctManagerName, acctInfoPrefix+strconv.FormatUint(acct.GetAccountID(), 10))
+	am.sdb.Delete(acctManagerName, accountNameIDPrefix+accountName.String())
+	delete(am.acctCache, accountName)
+	return nil
+}
+
 // GetNonce get nonce
 func (am *AccountManager) GetNonce(accountName common.Name) (uint64, error) {
 	acct, err := am.GetAccountByName(accountName)
@@ -587,6 +699,23 @@ func (am *AccountManager) SetNonce(accountName common.Name, nonce uint64) error
 	return am.SetAccount(acct)
 }
 
+// AddNonce increase nonce by delta
+func (am *AccountManager) AddNonce(accountName common.Name, delta uint64) error {
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return err
+	}
+	if acct == nil {
+		return ErrAccountNotExist
+	}
+	nonce := acct.GetNonce()
+	if nonce+delta < nonce {
+		return ErrNonceOverflow
+	}
+	acct.SetNonce(nonce + delta)
+	return am.SetAccount(acct)
+}
+
 // GetAuthorVersion returns the account author version
 func (am *AccountManager) GetAuthorVersion(accountName common.Name) (common.Hash, error) {
 	acct, err := am.GetAccountByName(accountName)
@@ -619,6 +748,10 @@ func (am *AccountManager) getParentAccount(accountName common.Name, parentIndex
 }
 
 // RecoverTx Make sure the transaction is signed properly and validate account authorization.
+// The weight of the recovered signers of every account must reach the threshold of
+// the account, otherwise ErrInsufficientAuthorWeight is returned. The author versions
+// verified are cached in the action, a changed version forces the signature to be
+// recovered again before execution.
 func (am *AccountManager) RecoverTx(signer types.Signer, tx *types.Transaction) error {
 	authorVersion := make(map[common.Name]common.Hash)
 	for _, action := range tx.GetActions() {
@@ -658,7 +791,7 @@ func (am *AccountManager) RecoverTx(signer types.Signer, tx *types.Transaction)
 				threshold = acctAuthor.updateAuthorThreshold
 			}
 			if count < threshold {
-				return fmt.Errorf("account %s want threshold %d, but actual is %d", name, threshold, count)
+				return &ErrInsufficientAuthorWeight{Account: name, Required: threshold, Provided: count}
 			}
 			authorVersion[name] = acctAuthor.version
 		}
@@ -704,7 +837,7 @@ func (am *AccountManager) RecoverTx(signer types.Signer, tx *types.Transaction)
 				}
 				threshold := acctAuthor.threshold
 				if count < threshold {
-					return fmt.Errorf("account %s want threshold %d, but actual is %d", name, threshold, count)
+					return &ErrInsufficientAuthorWeight{Account: name, Required: threshold, Provided: count}
 				}
 				authorVersion[name] = acctAuthor.version
 			}
@@ -715,6 +848,53 @@ func (am *AccountManager) RecoverTx(signer types.Signer, tx *types.Transaction)
 	return nil
 }
 
+// RecoverTxBatch verifies the signatures of txs like RecoverTx and returns the error of
+// every tx at its index in txs. The public keys are recovered on a bounded pool of goroutines
+// and cached in the actions, then the authorizations are validated in order since the state
+// can't be read concurrently. The accounts are read from the state once per batch.
+func (am *AccountManager) RecoverTxBatch(signer types.Signer, txs []*types.Transaction) []error {
+	errs := make([]error, len(txs))
+	threads := runtime.NumCPU()
+	if len(txs) < threads {
+		threads = len(txs)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(start int) {
+			defer wg.Done()
+			for j := start; j < len(txs); j += threads {
+				errs[j] = recoverPubKeys(signer, txs[j])
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	batch := &AccountManager{sdb: am.sdb, ast: am.ast, acctCache: make(map[common.Name]*Account)}
+	for i, tx := range txs {
+		if errs[i] == nil {
+			errs[i] = batch.RecoverTx(signer, tx)
+		}
+	}
+	return errs
+}
+
+// recoverPubKeys recovers the sender and payer public keys of all actions of tx and
+// caches them in the actions.
+func recoverPubKeys(signer types.Signer, tx *types.Transaction) error {
+	for _, action := range tx.GetActions() {
+		if _, err := types.RecoverMultiKey(signer, action, tx); err != nil {
+			return err
+		}
+		if tx.PayerExist() {
+			if _, err := types.RecoverPayerMultiKey(signer, action, tx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // IsValidSign
 func (am *AccountManager) IsValidSign(accountName common.Name, pub common.PubKey) error {
 	acct, err := am.GetAccountByName(accountName)
@@ -927,6 +1107,32 @@ func (am *AccountManager) GetAccountBalanceByID(accountName common.Name, assetID
 	}
 }
 
+// AccountBalance balance of an asset held by account itself, sub asset balances
+// are not included, as GetAccountBalanceByID with typeID 0
+type AccountBalance struct {
+	AssetID uint64   `json:"assetID"`
+	Balance *big.Int `json:"balance"`
+}
+
+//GetAllAccountBalances get all non-zero asset balances of account
+func (am *AccountManager) GetAllAccountBalances(accountName common.Name) ([]*AccountBalance, error) {
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	if acct == nil {
+		return nil, ErrAccountNotExist
+	}
+	balances := make([]*AccountBalance, 0, len(acct.Balances))
+	for _, ab := range acct.GetBalancesList() {
+		if ab.Balance.Sign() == 0 {
+			continue
+		}
+		balances = append(balances, &AccountBalance{AssetID: ab.AssetID, Balance: new(big.Int).Set(ab.Balance)})
+	}
+	return balances, nil
+}
+
 //GetAssetAmountByTime get asset amount by time
 func (am *AccountManager) GetAssetAmountByTime(assetID uint64, time uint64) (*big.Int, error) {
 	return am.ast.GetAssetAmountByTime(assetID, time)
@@ -1030,6 +1236,94 @@ func (am *AccountManager) AddAccountBalanceByID(accountName common.Name, assetID
 	return am.SetAccount(acct)
 }
 
+func frozenKey(acct *Account, assetID uint64) string {
+	return acctFrozenPrefix + strconv.FormatUint(acct.GetAccountID(), 10) + "_" + strconv.FormatUint(assetID, 10)
+}
+
+func (am *AccountManager) getFrozenBalance(acct *Account, assetID uint64) (*big.Int, error) {
+	b, err := am.sdb.Get(acctManagerName, frozenKey(acct, assetID))
+	if err != nil {
+		return nil, err
+	}
+	frozen := big.NewInt(0)
+	if len(b) == 0 {
+		return frozen, nil
+	}
+	if err := rlp.DecodeBytes(b, frozen); err != nil {
+		return nil, err
+	}
+	return frozen, nil
+}
+
+//GetFrozenBalance get frozen balance by assetID
+func (am *AccountManager) GetFrozenBalance(accountName common.Name, assetID uint64) (*big.Int, error) {
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return big.NewInt(0), err
+	}
+	if acct == nil {
+		return big.NewInt(0), ErrAccountNotExist
+	}
+	return am.getFrozenBalance(acct, assetID)
+}
+
+//FreezeAsset move value from the spendable balance to the frozen balance
+func (am *AccountManager) FreezeAsset(accountName common.Name, assetID uint64, value *big.Int) error {
+	if value == nil || value.Sign() < 0 {
+		return ErrAmountValueInvalid
+	}
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return err
+	}
+	if acct == nil {
+		return ErrAccountNotExist
+	}
+	frozen, err := am.getFrozenBalance(acct, assetID)
+	if err != nil {
+		return err
+	}
+	if err := acct.SubBalanceByID(assetID, value); err != nil {
+		return err
+	}
+	b, err := rlp.EncodeToBytes(frozen.Add(frozen, value))
+	if err != nil {
+		return err
+	}
+	am.sdb.Put(acctManagerName, frozenKey(acct, assetID), b)
+	return am.SetAccount(acct)
+}
+
+//UnfreezeAsset move value from the frozen balance back to the spendable balance
+func (am *AccountManager) UnfreezeAsset(accountName common.Name, assetID uint64, value *big.Int) error {
+	if value == nil || value.Sign() < 0 {
+		return ErrAmountValueInvalid
+	}
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return err
+	}
+	if acct == nil {
+		return ErrAccountNotExist
+	}
+	frozen, err := am.getFrozenBalance(acct, assetID)
+	if err != nil {
+		return err
+	}
+	if frozen.Cmp(value) < 0 {
+		return ErrInsufficientFrozen
+	}
+	if _, err := acct.AddBalanceByID(assetID, value); err != nil {
+		return err
+	}
+	b, err := rlp.EncodeToBytes(frozen.Sub(frozen, value))
+	if err != nil {
+		return err
+	}
+	am.sdb.Put(acctManagerName, frozenKey(acct, assetID), b)
+	return am.SetAccount(acct)
+}
+
 //AddAccountBalanceByName  add balance by name
 func (am *AccountManager) AddAccountBalanceByName(accountName common.Name, assetName string, value *big.Int) error {
 	acct, err := am.GetAccountByName(accountName)
@@ -1228,6 +1522,63 @@ func (am *AccountManager) TransferAsset(fromAccount common.Name, toAccount commo
 	return am.SetAccount(toAcct)
 }
 
+// Transfer is one transfer of a batch
+type Transfer struct {
+	To      common.Name
+	AssetID uint64
+	Value   *big.Int
+}
+
+// BatchTransferAsset transfer assets from one account to many accounts atomically
+func (am *AccountManager) BatchTransferAsset(fromAccount common.Name, transfers []*Transfer) error {
+	fromAcct, err := am.GetAccountByName(fromAccount)
+	if err != nil {
+		return err
+	}
+	if fromAcct == nil {
+		return ErrAccountNotExist
+	}
+
+	debits := make(map[uint64]*big.Int)
+	for i, transfer := range transfers {
+		if transfer.Value == nil || transfer.Value.Sign() < 0 {
+			return fmt.Errorf("transfer %v: %v", i, ErrNegativeValue)
+		}
+		if !am.ast.HasAccess(transfer.AssetID, fromAccount, transfer.To) {
+			return fmt.Errorf("transfer %v: no permissions of asset %v", i, transfer.AssetID)
+		}
+		toAcct, err := am.GetAccountByName(transfer.To)
+		if err != nil {
+			return fmt.Errorf("transfer %v: %v", i, err)
+		}
+		if toAcct == nil {
+			return fmt.Errorf("transfer %v: %v %v", i, ErrAccountNotExist, transfer.To)
+		}
+		if toAcct.IsDestroyed() {
+			return fmt.Errorf("transfer %v: %v %v", i, ErrAccountIsDestroy, transfer.To)
+		}
+
+		debit, ok := debits[transfer.AssetID]
+		if !ok {
+			debit = new(big.Int)
+			debits[transfer.AssetID] = debit
+		}
+		debit.Add(debit, transfer.Value)
+		if err := fromAcct.EnoughAccountBalance(transfer.AssetID, debit); err != nil {
+			return fmt.Errorf("transfer %v: %v", i, err)
+		}
+	}
+
+	snapshot := am.sdb.Snapshot()
+	for i, transfer := range transfers {
+		if err := am.TransferAsset(fromAccount, transfer.To, transfer.AssetID, transfer.Value); err != nil {
+			am.sdb.RevertToSnapshot(snapshot)
+			return fmt.Errorf("transfer %v: %v", i, err)
+		}
+	}
+	return nil
+}
+
 func (am *AccountManager) CheckAssetContract(contract common.Name, owner common.Name, from ...common.Name) bool {
 	from = append(from, owner)
 	for _, name := range from {
@@ -1396,6 +1747,90 @@ func (am *AccountManager) IncAsset2Acct(fromName common.Name, toName common.Name
 	return nil
 }
 
+//CreateAsset issue an asset and transfer the initial supply to the owner like an IssueAsset action,
+//it returns the asset id and the internal actions of the supply transfer
+func (am *AccountManager) CreateAsset(fromName common.Name, desc IssueAsset, number uint64, curForkID uint64, config *params.ChainConfig) (uint64, []*types.InternalAction, error) {
+	if desc.Decimals > maxAssetDecimals {
+		return 0, nil, fmt.Errorf("asset decimals %d exceed %d", desc.Decimals, maxAssetDecimals)
+	}
+	if desc.Amount == nil {
+		return 0, nil, ErrAmountValueInvalid
+	}
+	if desc.Amount.Sign() < 0 {
+		return 0, nil, ErrNegativeAmount
+	}
+	if desc.UpperLimit == nil {
+		desc.UpperLimit = big.NewInt(0)
+	}
+	if desc.UpperLimit.Sign() > 0 && desc.Amount.Cmp(desc.UpperLimit) > 0 {
+		return 0, nil, fmt.Errorf("asset amount %v exceed upper limit %v", desc.Amount, desc.UpperLimit)
+	}
+	if id, err := am.ast.GetAssetIDBySymbol(desc.Symbol); err == nil {
+		return 0, nil, fmt.Errorf("asset symbol %s is used by asset %d", desc.Symbol, id)
+	} else if err != asset.ErrAssetNotExist {
+		return 0, nil, err
+	}
+
+	assetID, err := am.IssueAsset(fromName, desc, number, curForkID)
+	if err != nil {
+		return 0, nil, err
+	}
+	internalActions, err := am.mintAsset(common.Name(config.AssetName), desc.Owner, assetID, desc.Amount, fromName)
+	if err != nil {
+		return 0, nil, err
+	}
+	return assetID, internalActions, nil
+}
+
+//UpdateAssetOwner set the new owner of asset, fromName must be the current owner
+func (am *AccountManager) UpdateAssetOwner(fromName common.Name, assetID uint64, owner common.Name) error {
+	acct, err := am.GetAccountByName(owner)
+	if err != nil {
+		return err
+	}
+	if acct == nil {
+		return ErrAccountNotExist
+	}
+
+	// check owner
+	if err := am.ast.CheckOwner(fromName, assetID); err != nil {
+		return err
+	}
+	return am.ast.SetAssetNewOwner(fromName, assetID, owner)
+}
+
+//AddAssetSupply increase asset and transfer amount to toName like an IncreaseAsset action,
+//fromName must be the owner
+func (am *AccountManager) AddAssetSupply(fromName common.Name, toName common.Name, assetID uint64, amount *big.Int, forkID uint64, config *params.ChainConfig) ([]*types.InternalAction, error) {
+	if amount == nil {
+		return nil, ErrAmountValueInvalid
+	}
+	if amount.Sign() < 0 {
+		return nil, ErrNegativeAmount
+	}
+	if err := am.IncAsset2Acct(fromName, toName, assetID, amount, forkID); err != nil {
+		return nil, err
+	}
+	return am.mintAsset(common.Name(config.AssetName), toName, assetID, amount, fromName)
+}
+
+// mintAsset adds the new amount of asset to the asset account and transfers it to
+// toName, it returns the internal actions of the mint and of the transfer.
+func (am *AccountManager) mintAsset(assetAccount common.Name, toName common.Name, assetID uint64, amount *big.Int, fromAccountExtra ...common.Name) ([]*types.InternalAction, error) {
+	if err := am.AddAccountBalanceByID(assetAccount, assetID, amount); err != nil {
+		return nil, err
+	}
+	actionX := types.NewAction(types.Transfer, common.Name(""), assetAccount, 0, assetID, 0, amount, nil, nil)
+	internalActions := []*types.InternalAction{{Action: actionX.NewRPCAction(0), ActionType: "", GasUsed: 0, GasLimit: 0, Depth: 0, Error: ""}}
+
+	if err := am.TransferAsset(assetAccount, toName, assetID, amount, fromAccountExtra...); err != nil {
+		return nil, err
+	}
+	actionX = types.NewAction(types.Transfer, assetAccount, toName, 0, assetID, 0, amount, nil, nil)
+	internalActions = append(internalActions, &types.InternalAction{Action: actionX.NewRPCAction(0), ActionType: "", GasUsed: 0, GasLimit: 0, Depth: 0, Error: ""})
+	return internalActions, nil
+}
+
 //Process account action
 func (am *AccountManager) Process(accountManagerContext *types.AccountManagerContext) ([]*types.InternalAction, error) {
 	snap := am.sdb.Snapshot()
@@ -1482,19 +1917,11 @@ func (am *AccountManager) process(accountManagerContext *types.AccountManagerCon
 			return nil, err
 		}
 
-		if err := am.AddAccountBalanceByID(common.Name(accountManagerContext.ChainConfig.AssetName), assetID, issueAsset.Amount); err != nil {
-			return nil, err
-		}
-		actionX := types.NewAction(types.Transfer, common.Name(""), common.Name(accountManagerContext.ChainConfig.AssetName), 0, assetID, 0, issueAsset.Amount, nil, nil)
-		internalAction := &types.InternalAction{Action: actionX.NewRPCAction(0), ActionType: "", GasUsed: 0, GasLimit: 0, Depth: 0, Error: ""}
-		internalActions = append(internalActions, internalAction)
-
-		if err := am.TransferAsset(common.Name(accountManagerContext.ChainConfig.AssetName), issueAsset.Owner, assetID, issueAsset.Amount, fromAccountExtra...); err != nil {
+		mintActions, err := am.mintAsset(common.Name(accountManagerContext.ChainConfig.AssetName), issueAsset.Owner, assetID, issueAsset.Amount, fromAccountExtra...)
+		if err != nil {
 			return nil, err
 		}
-		actionX = types.NewAction(types.Transfer, common.Name(accountManagerContext.ChainConfig.AssetName), issueAsset.Owner, 0, assetID, 0, issueAsset.Amount, nil, nil)
-		internalAction = &types.InternalAction{Action: actionX.NewRPCAction(0), ActionType: "", GasUsed: 0, GasLimit: 0, Depth: 0, Error: ""}
-		internalActions = append(internalActions, internalAction)
+		internalActions = append(internalActions, mintActions...)
 	case types.IncreaseAsset:
 		var inc IncAsset
 		err := rlp.DecodeBytes(action.Data(), &inc)
@@ -1510,20 +1937,12 @@ func (am *AccountManager) process(accountManagerContext *types.AccountManagerCon
 			return nil, err
 		}
 
-		if err := am.AddAccountBalanceByID(common.Name(accountManagerContext.ChainConfig.AssetName), inc.AssetID, inc.Amount); err != nil {
-			return nil, err
-		}
-		actionX := types.NewAction(types.Transfer, common.Name(""), common.Name(accountManagerContext.ChainConfig.AssetName), 0, inc.AssetID, 0, inc.Amount, nil, nil)
-		internalAction := &types.InternalAction{Action: actionX.NewRPCAction(0), ActionType: "", GasUsed: 0, GasLimit: 0, Depth: 0, Error: ""}
-		internalActions = append(internalActions, internalAction)
-
 		fromAccountExtra = append(fromAccountExtra, action.Sender())
-		if err := am.TransferAsset(common.Name(accountManagerContext.ChainConfig.AssetName), inc.To, inc.AssetID, inc.Amount, fromAccountExtra...); err != nil {
+		mintActions, err := am.mintAsset(common.Name(accountManagerContext.ChainConfig.AssetName), inc.To, inc.AssetID, inc.Amount, fromAccountExtra...)
+		if err != nil {
 			return nil, err
 		}
-		actionX = types.NewAction(types.Transfer, common.Name(accountManagerContext.ChainConfig.AssetName), inc.To, 0, inc.AssetID, 0, inc.Amount, nil, nil)
-		internalAction = &types.InternalAction{Action: actionX.NewRPCAction(0), ActionType: "", GasUsed: 0, GasLimit: 0, Depth: 0, Error: ""}
-		internalActions = append(internalActions, internalAction)
+		internalActions = append(internalActions, mintActions...)
 	case types.DestroyAsset:
 		if err := am.SubAccountBalanceByID(common.Name(accountManagerContext.ChainConfig.AssetName), action.AssetID(), action.Value()); err != nil {
 			return nil, err
@@ -1565,20 +1984,7 @@ func (am *AccountManager) process(accountManagerContext *types.AccountManagerCon
 		if err != nil {
 			return nil, err
 		}
-		acct, err := am.GetAccountByName(asset.Owner)
-		if err != nil {
-			return nil, err
-		}
-		if acct == nil {
-			return nil, ErrAccountNotExist
-		}
-
-		// check owner
-		if err := am.ast.CheckOwner(action.Sender(), asset.AssetID); err != nil {
-			return nil, err
-		}
-
-		if err := am.ast.SetAssetNewOwner(action.Sender(), asset.AssetID, asset.Owner); err != nil {
+		if err := am.UpdateAssetOwner(action.Sender(), asset.AssetID, asset.Owner); err != nil {
 			return nil, err
 		}
 	case types.UpdateAssetContract:
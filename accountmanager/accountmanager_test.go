@@ -18,9 +18,12 @@ package accountmanager
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/fractalplatform/fractal/asset"
@@ -379,6 +382,32 @@ func TestAccountManager_SetNonce(t *testing.T) {
 	}
 }
 
+func TestAccountManager_AddNonce(t *testing.T) {
+	name := common.Name("a123456789aeee")
+	nonce, err := accountManager.GetNonce(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accountManager.AddNonce(name, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := accountManager.GetNonce(name); got != nonce+1 {
+		t.Errorf("AddNonce(1) nonce = %d, want %d", got, nonce+1)
+	}
+	if err := accountManager.AddNonce(name, 5); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := accountManager.GetNonce(name); got != nonce+6 {
+		t.Errorf("AddNonce(5) nonce = %d, want %d", got, nonce+6)
+	}
+	if err := accountManager.AddNonce(name, math.MaxUint64); err != ErrNonceOverflow {
+		t.Errorf("AddNonce overflow err = %v, want %v", err, ErrNonceOverflow)
+	}
+	if err := accountManager.SetNonce(name, nonce); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestAccountManager_GetNonce(t *testing.T) {
 	type fields struct {
 		sdb *state.StateDB
@@ -1340,6 +1369,179 @@ func TestAccountManager_TransferAsset(t *testing.T) {
 
 }
 
+func TestAccountManager_BatchTransferAsset(t *testing.T) {
+	from, to := common.Name("a123456789aeee"), common.Name("a123456789aeed")
+	fromVal, _ := accountManager.GetAccountBalanceByID(from, 0, 0)
+	toVal, _ := accountManager.GetAccountBalanceByID(to, 0, 0)
+
+	tests := []struct {
+		name      string
+		transfers []*Transfer
+		wantErr   string
+	}{
+		{"insufficient", []*Transfer{{to, 0, big.NewInt(3)}, {to, 0, fromVal}}, "transfer 1: insufficient balance"},
+		{"unknownAccount", []*Transfer{{to, 0, big.NewInt(3)}, {common.Name("a123456789zzz"), 0, big.NewInt(3)}}, "transfer 1: account not exist"},
+		{"negative", []*Transfer{{to, 0, big.NewInt(-3)}}, "transfer 0: negative value"},
+		{"transferOk", []*Transfer{{to, 0, big.NewInt(3)}, {to, 0, big.NewInt(4)}}, ""},
+	}
+	for _, tt := range tests {
+		err := accountManager.BatchTransferAsset(from, tt.transfers)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%q. AccountManager.BatchTransferAsset() error = %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+			t.Errorf("%q. AccountManager.BatchTransferAsset() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	val, _ := accountManager.GetAccountBalanceByID(from, 0, 0)
+	if want := new(big.Int).Sub(fromVal, big.NewInt(7)); val.Cmp(want) != 0 {
+		t.Errorf("BatchTransferAsset from balance %v, want %v", val, want)
+	}
+	val, _ = accountManager.GetAccountBalanceByID(to, 0, 0)
+	if want := new(big.Int).Add(toVal, big.NewInt(7)); val.Cmp(want) != 0 {
+		t.Errorf("BatchTransferAsset to balance %v, want %v", val, want)
+	}
+
+	// restore balances for the following tests
+	if err := accountManager.TransferAsset(to, from, 0, big.NewInt(7)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAccountManager_FreezeAsset(t *testing.T) {
+	name := common.Name("a123456789aeee")
+	balance, _ := accountManager.GetAccountBalanceByID(name, 0, 0)
+
+	if err := accountManager.FreezeAsset(name, 0, nil); err != ErrAmountValueInvalid {
+		t.Errorf("FreezeAsset nil value err = %v, want %v", err, ErrAmountValueInvalid)
+	}
+	if err := accountManager.UnfreezeAsset(name, 0, nil); err != ErrAmountValueInvalid {
+		t.Errorf("UnfreezeAsset nil value err = %v, want %v", err, ErrAmountValueInvalid)
+	}
+	if err := accountManager.FreezeAsset(name, 0, new(big.Int).Add(balance, big.NewInt(1))); err != ErrInsufficientBalance {
+		t.Errorf("FreezeAsset over balance err = %v, want %v", err, ErrInsufficientBalance)
+	}
+	if err := accountManager.FreezeAsset(name, 0, big.NewInt(10)); err != nil {
+		t.Fatalf("FreezeAsset err = %v", err)
+	}
+	if frozen, _ := accountManager.GetFrozenBalance(name, 0); frozen.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("GetFrozenBalance = %v, want 10", frozen)
+	}
+	spendable, _ := accountManager.GetAccountBalanceByID(name, 0, 0)
+	if want := new(big.Int).Sub(balance, big.NewInt(10)); spendable.Cmp(want) != 0 {
+		t.Errorf("spendable balance = %v, want %v", spendable, want)
+	}
+	if err := accountManager.TransferAsset(name, common.Name("a123456789aeed"), 0, balance); err != ErrInsufficientBalance {
+		t.Errorf("TransferAsset frozen funds err = %v, want %v", err, ErrInsufficientBalance)
+	}
+
+	if err := accountManager.UnfreezeAsset(name, 0, big.NewInt(11)); err != ErrInsufficientFrozen {
+		t.Errorf("UnfreezeAsset over frozen err = %v, want %v", err, ErrInsufficientFrozen)
+	}
+	if err := accountManager.UnfreezeAsset(name, 0, big.NewInt(10)); err != nil {
+		t.Fatalf("UnfreezeAsset err = %v", err)
+	}
+	if frozen, _ := accountManager.GetFrozenBalance(name, 0); frozen.Sign() != 0 {
+		t.Errorf("GetFrozenBalance = %v, want 0", frozen)
+	}
+	if val, _ := accountManager.GetAccountBalanceByID(name, 0, 0); val.Cmp(balance) != 0 {
+		t.Errorf("balance after unfreeze = %v, want %v", val, balance)
+	}
+}
+
+func TestAccountManager_GetAllAccountBalances(t *testing.T) {
+	name := common.Name("a123456789aeee")
+	balance, _ := accountManager.GetAccountBalanceByID(name, 0, 0)
+	balances, err := accountManager.GetAllAccountBalances(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balances) != 1 || balances[0].AssetID != 0 || balances[0].Balance.Cmp(balance) != 0 {
+		t.Errorf("GetAllAccountBalances = %v, want asset 0 balance %v", balances, balance)
+	}
+
+	if err := accountManager.CreateAccount(common.Name("fractal.founder"), common.Name("a123456789aeec"), common.Name(""), 0, 0, *new(common.PubKey), ""); err != nil {
+		t.Fatal(err)
+	}
+	balances, err = accountManager.GetAllAccountBalances(common.Name("a123456789aeec"))
+	if err != nil || balances == nil || len(balances) != 0 {
+		t.Errorf("GetAllAccountBalances of empty account = %v, %v, want empty", balances, err)
+	}
+}
+
+func TestAccountManager_RecoverTxThreshold(t *testing.T) {
+	signer := types.NewSigner(params.DefaultChainconfig.ChainID)
+	keys := make([]*ecdsa.PrivateKey, 3)
+	pubs := make([]common.PubKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		pubs[i] = common.BytesToPubKey(crypto.FromECDSAPub(&keys[i].PublicKey))
+	}
+
+	name := common.Name("a123456789msig")
+	if err := accountManager.CreateAccount(common.Name("fractal.founder"), name, common.Name(""), 0, 0, pubs[0], ""); err != nil {
+		t.Fatal(err)
+	}
+	recoverTx := func(indexes ...uint64) (*types.Action, error) {
+		action := types.NewAction(types.Transfer, name, common.Name("a123456789aeed"), 0, 0, 0, big.NewInt(0), nil, nil)
+		tx := types.NewTransaction(0, big.NewInt(1), action)
+		keyPairs := make([]*types.KeyPair, 0, len(indexes))
+		for _, index := range indexes {
+			keyPairs = append(keyPairs, types.MakeKeyPair(keys[index], []uint64{index}))
+		}
+		if err := types.SignActionWithMultiKey(action, tx, signer, 0, keyPairs); err != nil {
+			t.Fatal(err)
+		}
+		return action, accountManager.RecoverTx(signer, tx)
+	}
+	wantWeightErr := func(err error, required, provided uint64) {
+		weightErr, ok := err.(*ErrInsufficientAuthorWeight)
+		if !ok {
+			t.Fatalf("RecoverTx err = %v, want ErrInsufficientAuthorWeight", err)
+		}
+		if weightErr.Account != name || weightErr.Required != required || weightErr.Provided != provided {
+			t.Errorf("RecoverTx err = %+v, want required %d provided %d", weightErr, required, provided)
+		}
+	}
+
+	// single signature account
+	if _, err := recoverTx(0); err != nil {
+		t.Fatalf("single sign RecoverTx err = %v", err)
+	}
+
+	// 2 of 3 account
+	if err := accountManager.UpdateAccountAuthor(name, &AccountAuthorAction{
+		Threshold: 2,
+		AuthorActions: []*AuthorAction{
+			{ActionType: AddAuthor, Author: common.NewAuthor(pubs[1], 1)},
+			{ActionType: AddAuthor, Author: common.NewAuthor(pubs[2], 1)},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := recoverTx(1)
+	wantWeightErr(err, 2, 1)
+	action, err := recoverTx(0, 2)
+	if err != nil {
+		t.Fatalf("2 of 3 RecoverTx err = %v", err)
+	}
+
+	// stale author version
+	cached := types.GetAuthorCache(action)[name]
+	if err := accountManager.UpdateAccountAuthor(name, &AccountAuthorAction{Threshold: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := accountManager.GetAuthorVersion(name); version == cached {
+		t.Fatal("author version not changed")
+	}
+	_, err = recoverTx(0, 2)
+	wantWeightErr(err, 3, 2)
+}
+
 func TestAccountManager_IssueAsset(t *testing.T) {
 	type fields struct {
 		sdb *state.StateDB
@@ -2312,3 +2514,246 @@ func TestAccountManager_AccountHaveCode(t *testing.T) {
 		t.Errorf("TestAccountManager_AccountHaveCode. account not have code error = %v", err)
 	}
 }
+
+func TestAccountManager_CreateAsset(t *testing.T) {
+	owner, other := common.Name("a123456789aeee"), common.Name("a123456789aeed")
+	desc := IssueAsset{
+		AssetName:  "zizcreate1234",
+		Symbol:     "zcs",
+		Amount:     big.NewInt(100),
+		Decimals:   8,
+		Owner:      owner,
+		UpperLimit: big.NewInt(1000),
+	}
+	config := params.DefaultChainconfig
+	assetID, internalActions, err := accountManager.CreateAsset(owner, desc, blockNumber, 0, config)
+	if err != nil {
+		t.Fatal("CreateAsset err", err)
+	}
+	if val, _ := accountManager.GetAccountBalanceByID(owner, assetID, 0); val.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("owner balance = %v, want 100", val)
+	}
+	if val, _ := accountManager.GetAccountBalanceByID(common.Name(config.AssetName), assetID, 0); val.Sign() != 0 {
+		t.Errorf("asset account balance = %v, want 0", val)
+	}
+	// the same transfers as an IssueAsset action, minted to the asset account and then moved to the owner
+	if len(internalActions) != 2 || internalActions[0].Action.To != common.Name(config.AssetName) ||
+		internalActions[1].Action.From != common.Name(config.AssetName) || internalActions[1].Action.To != owner {
+		t.Errorf("CreateAsset internal actions = %v", internalActions)
+	}
+
+	invalid := []struct {
+		name   string
+		modify func(*IssueAsset)
+	}{
+		{"duplicateSymbol", func(d *IssueAsset) { d.AssetName = "zizcreate1235" }},
+		{"decimals", func(d *IssueAsset) { d.AssetName, d.Symbol, d.Decimals = "zizcreate1236", "zcd", 19 }},
+		{"negativeAmount", func(d *IssueAsset) { d.AssetName, d.Symbol, d.Amount = "zizcreate1237", "zcn", big.NewInt(-1) }},
+		{"nilAmount", func(d *IssueAsset) { d.AssetName, d.Symbol, d.Amount = "zizcreate1239", "zcz", nil }},
+		{"overLimit", func(d *IssueAsset) { d.AssetName, d.Symbol, d.Amount = "zizcreate1238", "zco", big.NewInt(1001) }},
+	}
+	for _, tt := range invalid {
+		d := desc
+		tt.modify(&d)
+		if _, _, err := accountManager.CreateAsset(owner, d, blockNumber, 0, config); err == nil {
+			t.Errorf("%q. AccountManager.CreateAsset() succeeded", tt.name)
+		}
+	}
+
+	if _, err := accountManager.AddAssetSupply(owner, other, assetID, nil, 0, config); err != ErrAmountValueInvalid {
+		t.Errorf("AddAssetSupply nil amount err = %v, want %v", err, ErrAmountValueInvalid)
+	}
+	if _, err := accountManager.AddAssetSupply(other, other, assetID, big.NewInt(5), 0, config); err == nil {
+		t.Error("AddAssetSupply by non owner succeeded")
+	}
+	if _, err := accountManager.AddAssetSupply(owner, other, assetID, big.NewInt(5), 0, config); err != nil {
+		t.Fatal("AddAssetSupply err", err)
+	}
+	if val, _ := accountManager.GetAccountBalanceByID(other, assetID, 0); val.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("supply balance = %v, want 5", val)
+	}
+
+	if err := accountManager.UpdateAssetOwner(other, assetID, other); err == nil {
+		t.Error("UpdateAssetOwner by non owner succeeded")
+	}
+	if err := accountManager.UpdateAssetOwner(owner, assetID, other); err != nil {
+		t.Fatal("UpdateAssetOwner err", err)
+	}
+	if _, err := accountManager.AddAssetSupply(owner, owner, assetID, big.NewInt(5), 0, config); err == nil {
+		t.Error("AddAssetSupply by previous owner succeeded")
+	}
+}
+
+func TestAccountManager_ForEachAccount(t *testing.T) {
+	var names []common.Name
+	if err := accountManager.ForEachAccount(func(acct *Account) error {
+		names = append(names, acct.GetName())
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for i, name := range names {
+		if name == "a123456789aeee" {
+			found = true
+		}
+		if i > 0 {
+			prev, _ := accountManager.GetAccountIDByName(names[i-1])
+			cur, _ := accountManager.GetAccountIDByName(name)
+			if prev >= cur {
+				t.Errorf("account %s (id %d) visited after %s (id %d)", name, cur, names[i-1], prev)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("account a123456789aeee not visited, got %v", names)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err := accountManager.ForEachAccount(func(acct *Account) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("ForEachAccount err = %v after %d calls, want %v after 1", err, count, stop)
+	}
+}
+
+func TestAccountManager_DestroyAccount(t *testing.T) {
+	src, beneficiary := common.Name("a123destroy1"), common.Name("a123destroy2")
+	for _, name := range []common.Name{src, beneficiary} {
+		if err := accountManager.CreateAccount(common.Name("fractal.founder"), name, common.Name(""), 0, 0, *new(common.PubKey), ""); err != nil {
+			t.Fatal("CreateAccount err", err)
+		}
+	}
+	amounts := []int64{100, 50}
+	assetIDs := make([]uint64, len(amounts))
+	for i, amount := range amounts {
+		desc := IssueAsset{
+			AssetName:  fmt.Sprintf("zizdestroy%d", i),
+			Symbol:     fmt.Sprintf("zd%d", i),
+			Amount:     big.NewInt(amount),
+			Decimals:   8,
+			Owner:      src,
+			UpperLimit: big.NewInt(1000),
+		}
+		id, _, err := accountManager.CreateAsset(src, desc, blockNumber, 0, params.DefaultChainconfig)
+		if err != nil {
+			t.Fatal("CreateAsset err", err)
+		}
+		assetIDs[i] = id
+	}
+
+	if err := accountManager.FreezeAsset(src, assetIDs[0], big.NewInt(10)); err != nil {
+		t.Fatal("FreezeAsset err", err)
+	}
+	notCandidate := func(common.Name) (bool, error) { return false, nil }
+	if err := accountManager.DestroyAccount(src, beneficiary, notCandidate); err == nil {
+		t.Error("DestroyAccount with frozen balance succeeded")
+	}
+	if err := accountManager.UnfreezeAsset(src, assetIDs[0], big.NewInt(10)); err != nil {
+		t.Fatal("UnfreezeAsset err", err)
+	}
+	if err := accountManager.DestroyAccount(src, src, notCandidate); err == nil {
+		t.Error("DestroyAccount to itself succeeded")
+	}
+	if err := accountManager.DestroyAccount(src, beneficiary, nil); err == nil {
+		t.Error("DestroyAccount without candidate check succeeded")
+	}
+	isCandidate := func(name common.Name) (bool, error) { return name == src, nil }
+	if err := accountManager.DestroyAccount(src, beneficiary, isCandidate); err == nil || !strings.Contains(err.Error(), "registered candidate") {
+		t.Errorf("DestroyAccount of candidate err = %v, want registered candidate", err)
+	}
+
+	if err := accountManager.DestroyAccount(src, beneficiary, notCandidate); err != nil {
+		t.Fatal("DestroyAccount err", err)
+	}
+	for i, id := range assetIDs {
+		if val, _ := accountManager.GetAccountBalanceByID(beneficiary, id, 0); val.Cmp(big.NewInt(amounts[i])) != 0 {
+			t.Errorf("beneficiary balance of asset %d = %v, want %d", id, val, amounts[i])
+		}
+	}
+	if acct, err := accountManager.GetAccountByName(src); err != nil || acct != nil {
+		t.Errorf("account %s not removed: %v, err %v", src, acct, err)
+	}
+	if id, err := accountManager.GetAccountIDByName(src); err != nil || id != 0 {
+		t.Errorf("name index of %s not removed: id %d, err %v", src, id, err)
+	}
+	if err := accountManager.DestroyAccount(src, beneficiary, notCandidate); err != ErrAccountNotExist {
+		t.Errorf("DestroyAccount twice err = %v, want %v", err, ErrAccountNotExist)
+	}
+}
+
+func TestAccountManager_ProcessCreateAccount(t *testing.T) {
+	name := common.Name("a123created1")
+	pubkey := new(common.PubKey)
+	pubkey.SetBytes([]byte("abcde123456789created"))
+	createAction := func(accountName common.Name) *types.Action {
+		payload, err := rlp.EncodeToBytes(&CreateAccountAction{AccountName: accountName, PublicKey: *pubkey})
+		if err != nil {
+			t.Fatal("rlp payload err", err)
+		}
+		return types.NewAction(types.CreateAccount, common.Name("a123456789aeee"), common.Name(params.DefaultChainconfig.AccountName), 1, 0, 2, big.NewInt(0), payload, nil)
+	}
+	process := func(action *types.Action) error {
+		_, err := accountManager.Process(&types.AccountManagerContext{Action: action, ChainConfig: params.DefaultChainconfig, Number: blockNumber})
+		return err
+	}
+
+	if err := process(createAction(name)); err != nil {
+		t.Fatal("Process CreateAccount err", err)
+	}
+	if nonce, err := accountManager.GetNonce(name); err != nil || nonce != 0 {
+		t.Errorf("GetNonce = %v, %v, want 0", nonce, err)
+	}
+	want, err := NewAccount(name, common.Name(""), *pubkey, "")
+	if err != nil {
+		t.Fatal("NewAccount err", err)
+	}
+	if version, err := accountManager.GetAuthorVersion(name); err != nil || version != want.GetAuthorVersion() {
+		t.Errorf("GetAuthorVersion = %x, %v, want %x", version, err, want.GetAuthorVersion())
+	}
+
+	if err := process(createAction(name)); err != ErrAccountIsExist {
+		t.Errorf("duplicate CreateAccount err = %v, want %v", err, ErrAccountIsExist)
+	}
+	if err := process(createAction(common.Name("a1.."))); err == nil {
+		t.Error("CreateAccount with malformed name succeeded")
+	}
+}
+
+func TestAccountManager_RecoverTxBatch(t *testing.T) {
+	signer := types.NewSigner(params.DefaultChainconfig.ChainID)
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	name := common.Name("a123456789batch")
+	if err := accountManager.CreateAccount(common.Name("fractal.founder"), name, common.Name(""), 0, 0, common.BytesToPubKey(crypto.FromECDSAPub(&key.PublicKey)), ""); err != nil {
+		t.Fatal(err)
+	}
+	signedTx := func(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+		action := types.NewAction(types.Transfer, name, common.Name("a123456789aeed"), nonce, 0, 0, big.NewInt(0), nil, nil)
+		tx := types.NewTransaction(0, big.NewInt(1), action)
+		if err := types.SignActionWithMultiKey(action, tx, signer, 0, []*types.KeyPair{types.MakeKeyPair(key, []uint64{0})}); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	txs := []*types.Transaction{signedTx(0, key), signedTx(1, otherKey), signedTx(2, key), signedTx(3, otherKey)}
+	errs := accountManager.RecoverTxBatch(signer, txs)
+	if len(errs) != len(txs) {
+		t.Fatalf("RecoverTxBatch returned %d errors for %d txs", len(errs), len(txs))
+	}
+	for i, err := range errs {
+		if want := accountManager.RecoverTx(signer, txs[i]); (err == nil) != (want == nil) {
+			t.Errorf("tx %d: RecoverTxBatch err = %v, RecoverTx err = %v", i, err, want)
+		}
+		if wantErr := i%2 == 1; (err != nil) != wantErr {
+			t.Errorf("tx %d: RecoverTxBatch err = %v, wantErr %v", i, err, wantErr)
+		}
+	}
+	if errs := accountManager.RecoverTxBatch(signer, nil); len(errs) != 0 {
+		t.Errorf("RecoverTxBatch of no txs = %v", errs)
+	}
+}
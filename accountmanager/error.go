@@ -16,10 +16,17 @@
 
 package accountmanager
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fractalplatform/fractal/common"
+)
 
 var (
 	ErrInsufficientBalance    = errors.New("insufficient balance")
+	ErrInsufficientFrozen     = errors.New("insufficient frozen balance")
+	ErrNonceOverflow          = errors.New("nonce overflow")
 	ErrNewAccountErr          = errors.New("new account err")
 	ErrAssetIDInvalid         = errors.New("asset id invalid")
 	ErrCreateAccountError     = errors.New("create account error")
@@ -53,3 +60,15 @@ var (
 	ErrAmountMustBeZero       = errors.New("amount must be zero")
 	ErrAssetOwnerInvalid      = errors.New("asset owner Invalid ")
 )
+
+// ErrInsufficientAuthorWeight is returned when the weight of the recovered
+// signers of an account is under the threshold of the account.
+type ErrInsufficientAuthorWeight struct {
+	Account  common.Name
+	Required uint64
+	Provided uint64
+}
+
+func (e *ErrInsufficientAuthorWeight) Error() string {
+	return fmt.Sprintf("account %s want threshold %d, but actual is %d", e.Account, e.Required, e.Provided)
+}
@@ -209,6 +209,24 @@ func (a *Asset) InitAssetCount() {
 // 	return assets, nil
 // }
 
+//GetAssetIDBySymbol get the id of the first asset with symbol
+func (a *Asset) GetAssetIDBySymbol(symbol string) (uint64, error) {
+	assetCount, err := a.getAssetCount()
+	if err != nil {
+		return 0, err
+	}
+	for id := uint64(0); id < assetCount; id++ {
+		ao, err := a.GetAssetObjectByID(id)
+		if err != nil {
+			return 0, err
+		}
+		if ao.GetSymbol() == symbol {
+			return id, nil
+		}
+	}
+	return 0, ErrAssetNotExist
+}
+
 //GetAssetObjectByName get asset object by name
 func (a *Asset) GetAssetObjectByName(assetName string) (*AssetObject, error) {
 	assetID, err := a.GetAssetIDByName(assetName)
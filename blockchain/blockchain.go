@@ -360,6 +360,24 @@ func (bc *BlockChain) insert(batch fdb.Batch, block *types.Block) {
 	}
 }
 
+// writeNameBloom indexes the account names touched by the txs of block, blocks
+// and sections without the queried names are skipped when scanning txs by name.
+func (bc *BlockChain) writeNameBloom(batch fdb.Batch, block *types.Block) {
+	number := block.NumberU64()
+	if rawdb.ReadNameBloomTail(bc.db) == nil {
+		rawdb.WriteNameBloomTail(batch, number)
+	}
+	bloom := types.CreateNameBloom(block.Transactions())
+	rawdb.WriteNameBloom(batch, block.Hash(), number, bloom)
+
+	// section bloom only grows, blocks dropped by reorg just cause false positive
+	section := number / rawdb.NameBloomSectionSize
+	if sbloom := rawdb.ReadNameBloomSection(bc.db, section); sbloom != nil {
+		bloom = types.BytesToBloom(new(big.Int).Or(sbloom.Big(), bloom.Big()).Bytes())
+	}
+	rawdb.WriteNameBloomSection(batch, section, bloom)
+}
+
 // Genesis retrieves the chain's genesis block.
 func (bc *BlockChain) Genesis() *types.Block {
 	return bc.genesisBlock
@@ -662,6 +680,7 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	}
 
 	rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
+	bc.writeNameBloom(batch, block)
 	if bc.vmConfig.ContractLogFlag {
 		detailTxs := make([]*types.DetailTx, len(receipts))
 		for i := 0; i < len(receipts); i++ {
@@ -678,10 +697,11 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 		reorg = block.NumberU64() < currentBlock.NumberU64() || (block.NumberU64() == currentBlock.NumberU64() && mrand.Float64() < 0.5)
 	}
 
+	var reorgEvent *ChainReorgEvent
 	if reorg {
 		// Reorganise the chain if the parent is not the head block
 		if block.ParentHash() != currentBlock.Hash() {
-			if err = bc.reorgChain(currentBlock, block, batch); err != nil {
+			if reorgEvent, err = bc.reorgChain(currentBlock, block, batch); err != nil {
 				return false, err
 			}
 		}
@@ -703,11 +723,24 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	if isCanon {
 		bc.currentBlock.Store(block)
 	}
+	if reorgEvent != nil && len(reorgEvent.Dropped) > 0 {
+		event.SendEvent(&event.Event{Typecode: event.ChainReorgEv, Data: reorgEvent})
+	}
 
 	bc.futureBlocks.Remove(block.Hash())
 	return isCanon, err
 }
 
+// OldestStateNumber returns the number of the oldest block whose state is kept. With
+// state pruning only the states of the recent blocks and of snapshot blocks are kept.
+func (bc *BlockChain) OldestStateNumber() uint64 {
+	current := bc.CurrentBlock().NumberU64()
+	if !bc.statePruning || current <= bc.triesInMemory {
+		return 0
+	}
+	return current - bc.triesInMemory
+}
+
 // StatePruning enable/disable state pruning
 func (bc *BlockChain) StatePruning(enable bool) (bool, uint64) {
 	bc.chainmu.Lock()
@@ -898,7 +931,18 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []*types.Log, error)
 	return 0, coalescedLogs, nil
 }
 
-func (bc *BlockChain) reorgChain(oldBlock, newBlock *types.Block, batch fdb.Batch) error {
+// ChainReorgEvent is posted when the canonical chain switched to another branch.
+// Dropped lists the blocks of the old branch from its head down, Added lists the
+// blocks of the new branch from the common ancestor up to the new head.
+type ChainReorgEvent struct {
+	CommonHash   common.Hash   `json:"commonHash"`
+	CommonNumber uint64        `json:"commonNumber"`
+	Dropped      []common.Hash `json:"dropped"`
+	Added        []common.Hash `json:"added"`
+	HeadNumber   uint64        `json:"headNumber"`
+}
+
+func (bc *BlockChain) reorgChain(oldBlock, newBlock *types.Block, batch fdb.Batch) (*ChainReorgEvent, error) {
 	var (
 		newChain    types.Blocks
 		oldChain    types.Blocks
@@ -918,10 +962,10 @@ func (bc *BlockChain) reorgChain(oldBlock, newBlock *types.Block, batch fdb.Batc
 	}
 
 	if oldBlock == nil {
-		return fmt.Errorf("reorg chain not found oldblock ")
+		return nil, fmt.Errorf("reorg chain not found oldblock ")
 	}
 	if newBlock == nil {
-		return fmt.Errorf("reorg chain not found newblock ")
+		return nil, fmt.Errorf("reorg chain not found newblock ")
 	}
 
 	for {
@@ -934,10 +978,10 @@ func (bc *BlockChain) reorgChain(oldBlock, newBlock *types.Block, batch fdb.Batc
 		deletedTxs = append(deletedTxs, oldBlock.Txs...)
 		oldBlock, newBlock = bc.GetBlock(oldBlock.ParentHash(), oldBlock.NumberU64()-1), bc.GetBlock(newBlock.ParentHash(), newBlock.NumberU64()-1)
 		if oldBlock == nil {
-			return fmt.Errorf("reorg chain not found old block ")
+			return nil, fmt.Errorf("reorg chain not found old block ")
 		}
 		if newBlock == nil {
-			return fmt.Errorf("reorg chain not found new block ")
+			return nil, fmt.Errorf("reorg chain not found new block ")
 		}
 	}
 
@@ -952,7 +996,7 @@ func (bc *BlockChain) reorgChain(oldBlock, newBlock *types.Block, batch fdb.Batc
 			"add", len(newChain), "addNum", newChain[0].NumberU64(), "addfrom", newChain[0].Hash())
 
 		if len(oldChain) > int(bc.triesInMemory) {
-			return fmt.Errorf("reorg chain too much,dropNum %v, drop %v", oldChain[0].NumberU64(), len(oldChain))
+			return nil, fmt.Errorf("reorg chain too much,dropNum %v, drop %v", oldChain[0].NumberU64(), len(oldChain))
 		}
 	} else {
 		// len(oldchain) = 0 when start with a specified block number
@@ -973,7 +1017,23 @@ func (bc *BlockChain) reorgChain(oldBlock, newBlock *types.Block, batch fdb.Batc
 	for _, tx := range diff {
 		rawdb.DeleteTxLookupEntry(batch, tx.Hash())
 	}
-	return nil
+
+	reorgEvent := &ChainReorgEvent{
+		CommonHash:   commonBlock.Hash(),
+		CommonNumber: commonBlock.NumberU64(),
+		Dropped:      make([]common.Hash, 0, len(oldChain)),
+		Added:        make([]common.Hash, 0, len(newChain)),
+	}
+	for _, block := range oldChain {
+		reorgEvent.Dropped = append(reorgEvent.Dropped, block.Hash())
+	}
+	for i := len(newChain) - 1; i >= 0; i-- {
+		reorgEvent.Added = append(reorgEvent.Added, newChain[i].Hash())
+	}
+	if len(newChain) > 0 {
+		reorgEvent.HeadNumber = newChain[0].NumberU64()
+	}
+	return reorgEvent, nil
 }
 
 func (bc *BlockChain) update() {
@@ -989,26 +1049,64 @@ func (bc *BlockChain) update() {
 	}
 }
 
+// BadBlock is a block rejected by the chain and the reason of rejection.
+type BadBlock struct {
+	Block  *types.Block
+	Reason string
+}
+
 // BadBlocks returns a list of the last 'bad blocks' that the client has seen on the network
-func (bc *BlockChain) BadBlocks() []*types.Block {
-	blocks := make([]*types.Block, 0, bc.badBlocks.Len())
+func (bc *BlockChain) BadBlocks() []*BadBlock {
+	blocks := make([]*BadBlock, 0, bc.badBlocks.Len())
 	for _, hash := range bc.badBlocks.Keys() {
 		if blk, exist := bc.badBlocks.Peek(hash); exist {
-			block := blk.(*types.Block)
+			block := blk.(*BadBlock)
 			blocks = append(blocks, block)
 		}
 	}
 	return blocks
 }
 
+// BadBlock returns the bad block with the given hash, or nil if it isn't in the bad-block cache.
+func (bc *BlockChain) BadBlock(hash common.Hash) *BadBlock {
+	if blk, exist := bc.badBlocks.Peek(hash); exist {
+		return blk.(*BadBlock)
+	}
+	return nil
+}
+
+// RemoveBadBlock removes the block from the bad-block cache and reports whether it was cached.
+func (bc *BlockChain) RemoveBadBlock(hash common.Hash) bool {
+	return bc.badBlocks.Remove(hash)
+}
+
+// ReprocessBlock executes the block on the state of its parent and validates the
+// resulting state like insertChain does, without writing anything to the chain.
+// The receipts of the executed txs are returned along with the error.
+func (bc *BlockChain) ReprocessBlock(block *types.Block, cfg vm.Config) ([]*types.Receipt, error) {
+	parent := bc.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent block %x of block %d not found", block.ParentHash(), block.NumberU64())
+	}
+	state, err := state.New(parent.Root(), bc.stateCache)
+	if err != nil {
+		return nil, err
+	}
+	receipts, _, usedGas, err := bc.processor.Process(block, state, cfg)
+	if err != nil {
+		return receipts, err
+	}
+	return receipts, bc.validator.ValidateState(block, parent, state, receipts, usedGas)
+}
+
 // addBadBlock adds a bad block to the bad-block LRU cache
-func (bc *BlockChain) addBadBlock(block *types.Block) {
-	bc.badBlocks.Add(block.Hash(), block)
+func (bc *BlockChain) addBadBlock(block *types.Block, err error) {
+	bc.badBlocks.Add(block.Hash(), &BadBlock{Block: block, Reason: err.Error()})
 }
 
 // reportBlock logs a bad block error.
 func (bc *BlockChain) reportBlock(block *types.Block, receipts []*types.Receipt, err error) {
-	bc.addBadBlock(block)
+	bc.addBadBlock(block, err)
 	log.Error(fmt.Sprintf(`
 ########## BAD BLOCK #########
 
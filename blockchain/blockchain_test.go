@@ -17,9 +17,13 @@
 package blockchain
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/event"
 	"github.com/fractalplatform/fractal/processor/vm"
 	"github.com/fractalplatform/fractal/txpool"
 )
@@ -69,6 +73,53 @@ func TestSystemForkChain(t *testing.T) {
 	checkCompleteChain(t, chain)
 }
 
+func TestChainReorgEvent(t *testing.T) {
+	genesis := DefaultGenesis()
+	chain := newCanonical(t, genesis)
+	defer chain.Stop()
+
+	chain, blocks := makeNewChain(t, genesis, chain, 10, canonicalSeed)
+
+	forkChain := newCanonical(t, genesis)
+	defer forkChain.Stop()
+
+	_, forkBlocks := makeNewChain(t, genesis, forkChain, 11, forkSeed)
+
+	ch := make(chan *event.Event, len(forkBlocks))
+	sub := event.Subscribe(nil, ch, event.ChainReorgEv, &ChainReorgEvent{})
+	defer sub.Unsubscribe()
+
+	if _, err := chain.InsertChain(forkBlocks); err != nil {
+		t.Fatal(err)
+	}
+
+	var ev *ChainReorgEvent
+	select {
+	case e := <-ch:
+		ev = e.Data.(*ChainReorgEvent)
+	case <-time.After(time.Second):
+		t.Fatal("no reorg event")
+	}
+	if len(ev.Dropped) == 0 || ev.Dropped[0] != blocks[len(blocks)-1].Hash() {
+		t.Fatalf("dropped %v, want old head %x first", ev.Dropped, blocks[len(blocks)-1].Hash())
+	}
+	forkHashes := make(map[common.Hash]bool)
+	for _, block := range forkBlocks {
+		forkHashes[block.Hash()] = true
+	}
+	for _, hash := range ev.Added {
+		if !forkHashes[hash] {
+			t.Errorf("added block %x not in fork", hash)
+		}
+	}
+	if ev.HeadNumber != ev.CommonNumber+uint64(len(ev.Added)) {
+		t.Errorf("head number %d, common %d, added %d", ev.HeadNumber, ev.CommonNumber, len(ev.Added))
+	}
+	if ancestor := chain.GetBlockByNumber(ev.CommonNumber); ancestor == nil || ancestor.Hash() != ev.CommonHash {
+		t.Errorf("common ancestor %x is not canonical", ev.CommonHash)
+	}
+}
+
 func TestBadBlockHashes(t *testing.T) {
 	genesis := DefaultGenesis()
 	chain := newCanonical(t, genesis)
@@ -108,3 +159,38 @@ func TestBadBlockHashes(t *testing.T) {
 	t.Log(newChain.CurrentBlock().Hash().String())
 	t.Log(newChain.Genesis().Hash().String())
 }
+
+func TestReprocessBadBlock(t *testing.T) {
+	genesis := DefaultGenesis()
+	chain := newCanonical(t, genesis)
+	defer chain.Stop()
+
+	chain, blocks := makeNewChain(t, genesis, chain, 5, canonicalSeed)
+	block := blocks[2]
+
+	receipts, err := chain.ReprocessBlock(block, vm.Config{})
+	if err != nil {
+		t.Fatalf("reprocess valid block: %v", err)
+	}
+	if len(receipts) != len(block.Transactions()) {
+		t.Errorf("receipts %d, want %d", len(receipts), len(block.Transactions()))
+	}
+
+	header := block.Header()
+	header.Root = common.Hash{}
+	tampered := block.WithSeal(header)
+	if _, err := chain.ReprocessBlock(tampered, vm.Config{}); err == nil {
+		t.Error("reprocess block with wrong state root succeeded")
+	}
+
+	chain.addBadBlock(tampered, errors.New("bad root"))
+	if bad := chain.BadBlock(tampered.Hash()); bad == nil || bad.Block.Hash() != tampered.Hash() {
+		t.Fatalf("bad block %x not cached", tampered.Hash())
+	}
+	if !chain.RemoveBadBlock(tampered.Hash()) {
+		t.Error("remove cached bad block failed")
+	}
+	if chain.BadBlock(tampered.Hash()) != nil || chain.RemoveBadBlock(tampered.Hash()) {
+		t.Error("bad block still cached after removal")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"strings"
 	"time"
@@ -51,7 +52,7 @@ type GenesisAccount struct {
 type GenesisCandidate struct {
 	Name  string   `json:"name,omitempty"`
 	Info  string   `json:"info,omitempty"`
-	Stake *big.Int `json:"stake,omitempty"`
+	Stake *big.Int `json:"stake,omitempty"` // quantity in unit stake
 }
 
 // GenesisAsset is an asset in the state of the genesis block.
@@ -66,6 +67,8 @@ type GenesisAsset struct {
 }
 
 // Genesis specifies the header fields, state of a genesis block.
+// Timestamp is the genesis block time in milliseconds, it is also the dpos
+// reference time, so changing it changes the genesis hash.
 type Genesis struct {
 	Config          *params.ChainConfig `json:"config,omitempty"`
 	Timestamp       uint64              `json:"timestamp,omitempty"`
@@ -104,10 +107,75 @@ func dposConfig(cfg *params.ChainConfig) *dpos.Config {
 	}
 }
 
+// Validate checks the invariants of the genesis specification before any state
+// is created, all violations are reported together in the returned error.
+func (g *Genesis) Validate() error {
+	if g.Config == nil {
+		return errGenesisNoConfig
+	}
+
+	var errs []string
+	accounts := map[string]bool{g.Config.ChainName: true}
+	for i, account := range g.AllocAccounts {
+		if account == nil || len(account.Name) == 0 {
+			errs = append(errs, fmt.Sprintf("AllocAccounts[%d]: empty name", i))
+			continue
+		}
+		if accounts[account.Name] {
+			errs = append(errs, fmt.Sprintf("AllocAccounts[%d]: duplicate name '%s'", i, account.Name))
+		}
+		accounts[account.Name] = true
+	}
+
+	for i, asset := range g.AllocAssets {
+		if asset == nil {
+			errs = append(errs, fmt.Sprintf("AllocAssets[%d]: empty asset", i))
+			continue
+		}
+		if !accounts[asset.Owner] {
+			errs = append(errs, fmt.Sprintf("AllocAssets[%d]: owner '%s' not in AllocAccounts", i, asset.Owner))
+		}
+		if len(asset.Founder) != 0 && !accounts[asset.Founder] {
+			errs = append(errs, fmt.Sprintf("AllocAssets[%d]: founder '%s' not in AllocAccounts", i, asset.Founder))
+		}
+		if asset.Amount == nil || asset.Amount.Sign() <= 0 {
+			errs = append(errs, fmt.Sprintf("AllocAssets[%d]: amount %v of '%s' must be positive", i, asset.Amount, asset.Name))
+		}
+	}
+
+	candidates := map[string]bool{g.Config.SysName: true}
+	for i, candidate := range g.AllocCandidates {
+		if candidate == nil {
+			errs = append(errs, fmt.Sprintf("AllocCandidates[%d]: empty candidate", i))
+			continue
+		}
+		if !accounts[candidate.Name] {
+			errs = append(errs, fmt.Sprintf("AllocCandidates[%d]: candidate '%s' not in AllocAccounts", i, candidate.Name))
+		}
+		if candidates[candidate.Name] {
+			errs = append(errs, fmt.Sprintf("AllocCandidates[%d]: candidate '%s' already registered", i, candidate.Name))
+		}
+		candidates[candidate.Name] = true
+		if candidate.Stake == nil || g.Config.DposCfg == nil {
+			continue
+		}
+		if min := g.Config.DposCfg.CandidateMinQuantity; min != nil && candidate.Stake.Cmp(min) < 0 {
+			errs = append(errs, fmt.Sprintf("AllocCandidates[%d]: stake %v of '%s' below candidateMinQuantity %v", i, candidate.Stake, candidate.Name, min))
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("invalid genesis: %v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 // SetupGenesisBlock The returned chain configuration is never nil.
 func SetupGenesisBlock(db fdb.Database, genesis *Genesis) (*params.ChainConfig, *dpos.Config, common.Hash, error) {
-	if genesis != nil && genesis.Config == nil {
-		return params.DefaultChainconfig, dposConfig(params.DefaultChainconfig), common.Hash{}, errGenesisNoConfig
+	if genesis != nil {
+		if err := genesis.Validate(); err != nil {
+			return params.DefaultChainconfig, dposConfig(params.DefaultChainconfig), common.Hash{}, err
+		}
 	}
 
 	// Just commit the new block if there is no stored genesis block.
@@ -400,12 +468,16 @@ func (g *Genesis) ToBlock(db fdb.Database) (*types.Block, []*types.Receipt, erro
 		if ok, err := accountManager.AccountIsExist(common.StrToName(candidate.Name)); !ok {
 			return nil, nil, fmt.Errorf("candidate %v is not exist %v", candidate.Name, err)
 		}
+		quantity := big.NewInt(0)
+		if candidate.Stake != nil {
+			quantity = new(big.Int).Set(candidate.Stake)
+		}
 		if err := sys.SetCandidate(&dpos.CandidateInfo{
 			Epoch:         epoch,
 			Name:          candidate.Name,
 			Info:          candidate.Info,
-			Quantity:      big.NewInt(0),
-			TotalQuantity: big.NewInt(0),
+			Quantity:      quantity,
+			TotalQuantity: new(big.Int).Set(quantity),
 			Number:        number.Uint64(),
 		}); err != nil {
 			return nil, nil, fmt.Errorf("genesis create candidate err %v", err)
@@ -550,6 +622,69 @@ func (g *Genesis) Commit(db fdb.Database) (*types.Block, error) {
 	return block, nil
 }
 
+// DumpGenesis reconstructs the genesis specification of the chain stored in db,
+// the genesis is recorded in the extra of the genesis block header.
+func DumpGenesis(db fdb.Database) (*Genesis, error) {
+	hash := rawdb.ReadCanonicalHash(db, 0)
+	if (hash == common.Hash{}) {
+		return nil, errors.New("genesis block not found")
+	}
+	header := rawdb.ReadHeader(db, hash, 0)
+	if header == nil {
+		return nil, fmt.Errorf("genesis header %v not found", hash.Hex())
+	}
+	genesis := new(Genesis)
+	if err := json.Unmarshal(header.Extra, genesis); err != nil {
+		return nil, fmt.Errorf("genesis json unmarshal err %v", err)
+	}
+	if genesis.Config == nil {
+		if genesis.Config = rawdb.ReadChainConfig(db, hash); genesis.Config == nil {
+			return nil, errors.New("Found genesis block without chain config")
+		}
+	}
+	return genesis, nil
+}
+
+// LoadGenesisFile reads a genesis specification from the JSON file at path,
+// the fields absent from the file take the values of DefaultGenesis.
+func LoadGenesisFile(path string) (*Genesis, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis file %v: %v", path, err)
+	}
+	// The config fields are decoded over a copy of the default config, the alloc
+	// entries are decoded on their own, json would merge them into the default
+	// entries at the same index.
+	defaults := DefaultGenesis()
+	genesis := &Genesis{Config: defaults.Config.Copy()}
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file %v: %v", path, err)
+	}
+
+	if genesis.Config == nil {
+		genesis.Config = defaults.Config.Copy()
+	}
+	if genesis.Timestamp == 0 {
+		genesis.Timestamp = defaults.Timestamp
+	}
+	if genesis.GasLimit == 0 {
+		genesis.GasLimit = defaults.GasLimit
+	}
+	if genesis.Difficulty == nil {
+		genesis.Difficulty = new(big.Int).Set(defaults.Difficulty)
+	}
+	if len(genesis.AllocAccounts) == 0 {
+		genesis.AllocAccounts = defaults.AllocAccounts
+	}
+	if len(genesis.AllocAssets) == 0 {
+		genesis.AllocAssets = defaults.AllocAssets
+	}
+	if len(genesis.AllocCandidates) == 0 {
+		genesis.AllocCandidates = defaults.AllocCandidates
+	}
+	return genesis, nil
+}
+
 // DefaultGenesis returns the ft net genesis block.
 func DefaultGenesis() *Genesis {
 	return &Genesis{
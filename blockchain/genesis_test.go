@@ -19,15 +19,21 @@ package blockchain
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/fractalplatform/fractal/common"
 	"github.com/fractalplatform/fractal/consensus/dpos"
 	"github.com/fractalplatform/fractal/params"
 	"github.com/fractalplatform/fractal/rawdb"
+	"github.com/fractalplatform/fractal/state"
 	"github.com/fractalplatform/fractal/utils/fdb"
 )
 
@@ -141,3 +147,269 @@ func TestSetupGenesis(t *testing.T) {
 		}
 	}
 }
+
+func TestDumpGenesis(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	if _, _, _, err := SetupGenesisBlock(db, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	genesis, err := DumpGenesis(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(Genesis)
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	block, _, err := loaded.ToBlock(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Hash() != defaultgenesisBlockHash {
+		t.Errorf("wrong dumped genesis hash, got %v, want %v", block.Hash().Hex(), defaultgenesisBlockHash.Hex())
+	}
+	if _, _, hash, err := SetupGenesisBlock(db, loaded); err != nil || hash != defaultgenesisBlockHash {
+		t.Errorf("setup dumped genesis failed, hash %v err %v", hash.Hex(), err)
+	}
+}
+
+func TestGenesisValidate(t *testing.T) {
+	if err := DefaultGenesis().Validate(); err != nil {
+		t.Fatalf("default genesis invalid: %v", err)
+	}
+
+	genesis := DefaultGenesis()
+	genesis.AllocAccounts = append(genesis.AllocAccounts, DefaultGenesisAccounts()[1])
+	genesis.AllocAssets = append(genesis.AllocAssets, &GenesisAsset{Name: "foo", Owner: "foo", Amount: big.NewInt(0)})
+	genesis.AllocCandidates = append(genesis.AllocCandidates,
+		&GenesisCandidate{Name: "bar"},
+		&GenesisCandidate{Name: params.DefaultChainconfig.SysName, Stake: big.NewInt(1)})
+
+	err := genesis.Validate()
+	if err == nil {
+		t.Fatal("invalid genesis passed validation")
+	}
+	for _, want := range []string{
+		"AllocAccounts[5]: duplicate name",
+		"AllocAssets[1]: owner 'foo' not in AllocAccounts",
+		"AllocAssets[1]: amount 0",
+		"AllocCandidates[0]: candidate 'bar' not in AllocAccounts",
+		"AllocCandidates[1]: candidate 'fractal.founder' already registered",
+		"AllocCandidates[1]: stake 1",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	if _, _, _, err := SetupGenesisBlock(rawdb.NewMemoryDatabase(), genesis); err == nil {
+		t.Error("setup invalid genesis succeeded")
+	}
+}
+
+func TestLoadGenesisFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := LoadGenesisFile(filepath.Join(dir, "missing.json")); err == nil || !strings.Contains(err.Error(), "failed to read") {
+		t.Errorf("missing file err %v", err)
+	}
+
+	path := filepath.Join(dir, "genesis.json")
+	if err := ioutil.WriteFile(path, []byte(`{"timestamp":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGenesisFile(path); err == nil || !strings.Contains(err.Error(), "invalid genesis file") {
+		t.Errorf("malformed file err %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`{"timestamp":1555776000000,"remark":"partial"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := LoadGenesisFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if genesis.Remark != "partial" {
+		t.Errorf("remark got %v, want partial", genesis.Remark)
+	}
+	defaults := DefaultGenesis()
+	if genesis.Config.ChainID.Cmp(defaults.Config.ChainID) != 0 ||
+		len(genesis.AllocAccounts) != len(defaults.AllocAccounts) ||
+		len(genesis.AllocAssets) != len(defaults.AllocAssets) {
+		t.Errorf("defaults not merged: %v", genesis)
+	}
+	if err := genesis.Validate(); err != nil {
+		t.Errorf("merged genesis invalid: %v", err)
+	}
+}
+
+func TestLoadPartialGenesisFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "genesis.json")
+	if err := ioutil.WriteFile(path, []byte(`{"config":{"chainId":5},"difficulty":7}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := LoadGenesisFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := DefaultGenesis()
+	if genesis.Config.ChainID.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("chainId got %v, want 5", genesis.Config.ChainID)
+	}
+	if genesis.Config.ChainName != defaults.Config.ChainName || genesis.Config.SysName != defaults.Config.SysName ||
+		genesis.Config.DposCfg.BlockInterval != defaults.Config.DposCfg.BlockInterval {
+		t.Errorf("config defaults not kept: %+v", genesis.Config)
+	}
+	if genesis.Timestamp != defaults.Timestamp || genesis.GasLimit != defaults.GasLimit {
+		t.Errorf("timestamp %v gasLimit %v, want %v %v", genesis.Timestamp, genesis.GasLimit, defaults.Timestamp, defaults.GasLimit)
+	}
+	if genesis.Difficulty.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("difficulty got %v, want 7", genesis.Difficulty)
+	}
+	if params.DefaultChainconfig.ChainID.Cmp(big.NewInt(5)) == 0 || params.GenesisDifficulty.Cmp(big.NewInt(7)) == 0 {
+		t.Errorf("genesis file modified the package defaults")
+	}
+	if err := genesis.Validate(); err != nil {
+		t.Errorf("partial genesis invalid: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`{"remark":"no timestamp"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if genesis, err = LoadGenesisFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if genesis.Timestamp != defaults.Timestamp || genesis.GasLimit != defaults.GasLimit ||
+		genesis.Difficulty == nil || genesis.Difficulty.Cmp(defaults.Difficulty) != 0 {
+		t.Errorf("timestamp %v gasLimit %v difficulty %v, want defaults", genesis.Timestamp, genesis.GasLimit, genesis.Difficulty)
+	}
+	block, _, err := genesis.ToBlock(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := defaults.Timestamp * uint64(time.Millisecond); block.Time().Uint64() != want {
+		t.Errorf("genesis time got %v, want %v", block.Time(), want)
+	}
+}
+
+func TestLoadGenesisFileAllocEntries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "genesis.json")
+	data := `{"allocAccounts":[{"name":"alice"}],"allocAssets":[{"name":"coin"}],"allocCandidates":[{"name":"alice"}]}`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := LoadGenesisFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(genesis.AllocAccounts) != 1 || len(genesis.AllocAssets) != 1 || len(genesis.AllocCandidates) != 1 {
+		t.Fatalf("alloc got %d accounts %d assets %d candidates, want 1 each",
+			len(genesis.AllocAccounts), len(genesis.AllocAssets), len(genesis.AllocCandidates))
+	}
+	if account := genesis.AllocAccounts[0]; *account != (GenesisAccount{Name: "alice"}) {
+		t.Errorf("account inherited default fields: %+v", account)
+	}
+	if asset := genesis.AllocAssets[0]; asset.Name != "coin" || asset.Owner != "" || asset.Amount != nil {
+		t.Errorf("asset inherited default fields: %+v", asset)
+	}
+	if candidate := genesis.AllocCandidates[0]; candidate.Name != "alice" || candidate.Info != "" || candidate.Stake != nil {
+		t.Errorf("candidate inherited default fields: %+v", candidate)
+	}
+	if DefaultGenesisAccounts()[0].Name == "alice" {
+		t.Errorf("genesis file modified the package defaults")
+	}
+}
+
+func TestGenesisTimestamp(t *testing.T) {
+	hashAt := func(timestamp uint64) common.Hash {
+		genesis := DefaultGenesis()
+		genesis.Config = genesis.Config.Copy()
+		genesis.Timestamp = timestamp
+		block, _, err := genesis.ToBlock(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := timestamp * uint64(time.Millisecond); block.Time().Uint64() != want {
+			t.Errorf("genesis time got %v, want %v", block.Time(), want)
+		}
+		return block.Hash()
+	}
+
+	if hashAt(1555776000000) != defaultgenesisBlockHash {
+		t.Errorf("default timestamp changed genesis hash")
+	}
+	if hashAt(1555776000000) != hashAt(1555776000000) {
+		t.Errorf("genesis hash not deterministic")
+	}
+	if hashAt(1555776001000) == defaultgenesisBlockHash {
+		t.Errorf("custom timestamp kept default genesis hash")
+	}
+}
+
+func TestGenesisAllocCandidates(t *testing.T) {
+	genesis := DefaultGenesis()
+	genesis.Config = genesis.Config.Copy()
+	name := genesis.Config.ChainName + ".cand"
+	genesis.AllocAccounts = append(genesis.AllocAccounts, &GenesisAccount{Name: name, Founder: genesis.Config.SysName})
+	genesis.AllocCandidates = []*GenesisCandidate{
+		&GenesisCandidate{Name: name, Info: "www.fractalproject.com", Stake: big.NewInt(20)},
+	}
+
+	db := rawdb.NewMemoryDatabase()
+	_, dcfg, hash, err := SetupGenesisBlock(db, genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == defaultgenesisBlockHash {
+		t.Errorf("candidates not reflected in genesis hash")
+	}
+	block, _, err := genesis.ToBlock(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Hash() != hash {
+		t.Errorf("genesis hash not deterministic, got %v, want %v", block.Hash().Hex(), hash.Hex())
+	}
+
+	statedb, err := state.New(rawdb.ReadBlock(db, hash, 0).Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sys := dpos.NewSystem(statedb, dcfg)
+	epoch, err := sys.GetLastestEpoch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidate, err := sys.GetCandidate(epoch, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if candidate == nil {
+		t.Fatalf("candidate %v not registered", name)
+	}
+	if candidate.Quantity.Cmp(big.NewInt(20)) != 0 || candidate.Info != "www.fractalproject.com" {
+		t.Errorf("candidate got %v, want quantity 20", candidate)
+	}
+}
@@ -92,6 +92,11 @@ func defaultFtServiceConfig() *ftservice.Config {
 		MetricsConf:     defaultMetricsConfig(),
 		ContractLogFlag: false,
 		StatePruning:    true,
+
+		RPCCallTimeout:        ftservice.DefaultRPCCallTimeout,
+		RPCEstimateGasTimeout: ftservice.DefaultRPCEstimateGasTimeout,
+		RPCMaxLookback:        ftservice.DefaultRPCMaxLookback,
+		RPCTdCacheSize:        ftservice.DefaultRPCTdCacheSize,
 	}
 }
 
@@ -275,6 +275,39 @@ func addFlags(flags *flag.FlagSet) {
 	)
 	viper.BindPFlag("ftservice.startnumber", flags.Lookup("start_number"))
 
+	// rpc evm execution timeouts
+	flags.DurationVar(
+		&ftCfgInstance.FtServiceCfg.RPCCallTimeout,
+		"rpc_calltimeout",
+		ftCfgInstance.FtServiceCfg.RPCCallTimeout,
+		"evm execution timeout of rpc call requests.",
+	)
+	viper.BindPFlag("ftservice.rpccalltimeout", flags.Lookup("rpc_calltimeout"))
+
+	flags.DurationVar(
+		&ftCfgInstance.FtServiceCfg.RPCEstimateGasTimeout,
+		"rpc_estimategastimeout",
+		ftCfgInstance.FtServiceCfg.RPCEstimateGasTimeout,
+		"total evm execution timeout of rpc estimate gas requests.",
+	)
+	viper.BindPFlag("ftservice.rpcestimategastimeout", flags.Lookup("rpc_estimategastimeout"))
+
+	flags.Uint64Var(
+		&ftCfgInstance.FtServiceCfg.RPCMaxLookback,
+		"rpc_maxlookback",
+		ftCfgInstance.FtServiceCfg.RPCMaxLookback,
+		"max number of blocks scanned by rpc tx filter requests.",
+	)
+	viper.BindPFlag("ftservice.rpcmaxlookback", flags.Lookup("rpc_maxlookback"))
+
+	flags.IntVar(
+		&ftCfgInstance.FtServiceCfg.RPCTdCacheSize,
+		"rpc_tdcachesize",
+		ftCfgInstance.FtServiceCfg.RPCTdCacheSize,
+		"number of block total difficulties cached for rpc requests.",
+	)
+	viper.BindPFlag("ftservice.rpctdcachesize", flags.Lookup("rpc_tdcachesize"))
+
 	// add bad block hashs
 	flags.StringSliceVar(
 		&ftCfgInstance.FtServiceCfg.BadHashes,
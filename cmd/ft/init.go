@@ -17,11 +17,8 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 
-	"github.com/fractalplatform/fractal/blockchain"
 	"github.com/fractalplatform/fractal/ftservice"
 	"github.com/spf13/cobra"
 )
@@ -48,21 +45,12 @@ func init() {
 // initGenesis will initialise the given JSON format genesis file and writes it as
 // the zero'd block (i.e. genesis) or will fail hard if it can't succeed.
 func initGenesis() error {
-	// Make sure we have a valid genesis JSON
-	genesis := blockchain.DefaultGenesis()
-	if len(ftCfgInstance.GenesisFile) != 0 {
-		file, err := os.Open(ftCfgInstance.GenesisFile)
-		if err != nil {
-			return fmt.Errorf("Failed to read genesis file: %v(%v)", ftCfgInstance.GenesisFile, err)
-		}
-		defer file.Close()
-
-		if err := json.NewDecoder(file).Decode(genesis); err != nil {
-			return fmt.Errorf("invalid genesis file: %v(%v)", ftCfgInstance.GenesisFile, err)
-		}
+	genesis, err := loadGenesis()
+	if err != nil {
+		return err
 	}
 
-	stack, err := makeNode()
+	stack, err := makeNodeWithGenesis(genesis)
 	if err != nil {
 		return err
 	}
@@ -17,7 +17,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
@@ -94,24 +93,32 @@ var RootCmd = &cobra.Command{
 }
 
 func makeNode() (*node.Node, error) {
-	genesis := blockchain.DefaultGenesis()
-	// set miner config
-	SetupMetrics()
-	// Make sure we have a valid genesis JSON
-	if len(ftCfgInstance.GenesisFile) != 0 {
-		log.Info("Reading read genesis file", "path", ftCfgInstance.GenesisFile)
-		file, err := os.Open(ftCfgInstance.GenesisFile)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to read genesis file: %v(%v)", ftCfgInstance.GenesisFile, err)
-		}
-		defer file.Close()
-
-		if err := json.NewDecoder(file).Decode(genesis); err != nil {
-			return nil, fmt.Errorf("invalid genesis file: %v(%v)", ftCfgInstance.GenesisFile, err)
-		}
-		ftCfgInstance.FtServiceCfg.Genesis = genesis
+	genesis, err := loadGenesis()
+	if err != nil {
+		return nil, err
+	}
+	return makeNodeWithGenesis(genesis)
+}
 
+// loadGenesis returns the genesis of the genesis file, or the default genesis if no file is given.
+func loadGenesis() (*blockchain.Genesis, error) {
+	// Make sure we have a valid genesis JSON
+	if len(ftCfgInstance.GenesisFile) == 0 {
+		return blockchain.DefaultGenesis(), nil
+	}
+	log.Info("Reading read genesis file", "path", ftCfgInstance.GenesisFile)
+	genesis, err := blockchain.LoadGenesisFile(ftCfgInstance.GenesisFile)
+	if err != nil {
+		return nil, err
 	}
+	ftCfgInstance.FtServiceCfg.Genesis = genesis
+	return genesis, nil
+}
+
+// makeNodeWithGenesis creates the node of the chain with the given genesis.
+func makeNodeWithGenesis(genesis *blockchain.Genesis) (*node.Node, error) {
+	// set miner config
+	SetupMetrics()
 	block, _, err := genesis.ToBlock(nil)
 	if err != nil {
 		return nil, err
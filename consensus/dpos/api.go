@@ -17,15 +17,19 @@
 package dpos
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/fractalplatform/fractal/common"
 	"github.com/fractalplatform/fractal/consensus"
+	"github.com/fractalplatform/fractal/event"
 	"github.com/fractalplatform/fractal/rpc"
+	"github.com/fractalplatform/fractal/types"
 )
 
 // API exposes dpos related methods for the RPC interface.
@@ -39,6 +43,22 @@ func (api *API) Info() interface{} {
 	return api.dpos.config
 }
 
+// GetConsensusParams get slot timing & epoch length
+func (api *API) GetConsensusParams() (*ConsensusParams, error) {
+	cfg := api.dpos.config
+	if cfg.BlockInterval == 0 || cfg.BlockFrequency == 0 {
+		return nil, fmt.Errorf("invalid dpos config")
+	}
+	return &ConsensusParams{
+		BlockInterval:      cfg.BlockInterval,
+		BlockFrequency:     cfg.BlockFrequency,
+		SlotsPerEpoch:      cfg.epochInterval() / (cfg.blockInterval() * cfg.BlockFrequency),
+		EpochInterval:      cfg.EpochInterval,
+		EpochLength:        cfg.epochInterval() / cfg.blockInterval(),
+		ValidatorsPerEpoch: cfg.CandidateScheduleSize,
+	}, nil
+}
+
 // Irreversible get irreversible info
 func (api *API) Irreversible() interface{} {
 	ret := map[string]interface{}{}
@@ -119,6 +139,9 @@ func (api *API) Candidates(epoch uint64, detail bool) (interface{}, error) {
 	}
 	sort.Sort(candidates)
 	if detail {
+		if err := api.fillStatus(sys, epoch, candidates); err != nil {
+			return nil, err
+		}
 		return candidates, nil
 	}
 	names := make([]string, 0, len(candidates))
@@ -137,7 +160,134 @@ func (api *API) Candidate(epoch uint64, name string) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return sys.GetCandidate(epoch, name)
+	candidate, err := sys.GetCandidate(epoch, name)
+	if err != nil || candidate == nil {
+		return nil, err
+	}
+	if err := api.fillStatus(sys, epoch, []*CandidateInfo{candidate}); err != nil {
+		return nil, err
+	}
+	return candidate, nil
+}
+
+// GetCandidateInfos get candidates info in the order of names, nil for unknown
+func (api *API) GetCandidateInfos(epoch uint64, names []string) ([]*CandidateInfo, error) {
+	if epoch == 0 {
+		epoch, _ = api.epoch(api.chain.CurrentHeader().Number.Uint64())
+	}
+	sys, err := api.system()
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]*CandidateInfo, len(names))
+	for i, name := range names {
+		if candidates[i], err = sys.GetCandidate(epoch, name); err != nil {
+			return nil, err
+		}
+	}
+	if err := api.fillStatus(sys, epoch, candidates); err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+const maxCandidatesPageSize = 100
+
+// GetCandidatesPaged get a window of candidates info sorted by sortBy,
+// which is one of "stake", "name", with "-" prefix for descending order.
+func (api *API) GetCandidatesPaged(epoch uint64, start uint64, limit uint64, sortBy string) ([]*CandidateInfo, error) {
+	if limit == 0 || limit > maxCandidatesPageSize {
+		return nil, fmt.Errorf("limit %v out of range (1, %v)", limit, maxCandidatesPageSize)
+	}
+	desc := strings.HasPrefix(sortBy, "-")
+	var less func(a, b *CandidateInfo) bool
+	switch strings.TrimPrefix(sortBy, "-") {
+	case "stake":
+		less = func(a, b *CandidateInfo) bool {
+			if val := a.TotalQuantity.Cmp(b.TotalQuantity); val != 0 {
+				return val < 0
+			}
+			return a.Name < b.Name
+		}
+	case "name":
+		less = func(a, b *CandidateInfo) bool { return a.Name < b.Name }
+	default:
+		return nil, fmt.Errorf("invalid sortBy %v", sortBy)
+	}
+
+	if epoch == 0 {
+		epoch, _ = api.epoch(api.chain.CurrentHeader().Number.Uint64())
+	}
+	sys, err := api.system()
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := sys.GetCandidates(epoch)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if desc {
+			return less(candidates[j], candidates[i])
+		}
+		return less(candidates[i], candidates[j])
+	})
+	if start >= uint64(len(candidates)) {
+		return []*CandidateInfo{}, nil
+	}
+	end := start + limit
+	if end > uint64(len(candidates)) {
+		end = uint64(len(candidates))
+	}
+	if err := api.fillStatus(sys, epoch, candidates[start:end]); err != nil {
+		return nil, err
+	}
+	return candidates[start:end], nil
+}
+
+// GetVoterInfo get the candidates voted by voter and the quantities
+func (api *API) GetVoterInfo(epoch uint64, voter string) ([]*VoterInfo, error) {
+	if epoch == 0 {
+		epoch, _ = api.epoch(api.chain.CurrentHeader().Number.Uint64())
+	}
+	sys, err := api.system()
+	if err != nil {
+		return nil, err
+	}
+	voters, err := sys.GetVotersByVoter(epoch, voter)
+	if err != nil {
+		return nil, err
+	}
+	if voters == nil {
+		voters = []*VoterInfo{}
+	}
+	return voters, nil
+}
+
+// GetCandidateVoters get a window of the voters of candidate
+func (api *API) GetCandidateVoters(epoch uint64, candidate string, start uint64, limit uint64) ([]*VoterInfo, error) {
+	if limit == 0 || limit > maxCandidatesPageSize {
+		return nil, fmt.Errorf("limit %v out of range (1, %v)", limit, maxCandidatesPageSize)
+	}
+	if epoch == 0 {
+		epoch, _ = api.epoch(api.chain.CurrentHeader().Number.Uint64())
+	}
+	sys, err := api.system()
+	if err != nil {
+		return nil, err
+	}
+	voters, err := sys.GetVotersByCandidate(epoch, candidate)
+	if err != nil {
+		return nil, err
+	}
+	if start >= uint64(len(voters)) {
+		return []*VoterInfo{}, nil
+	}
+	end := start + limit
+	if end > uint64(len(voters)) {
+		end = uint64(len(voters))
+	}
+	return voters[start:end], nil
 }
 
 // VotersByCandidate get voters info of candidate
@@ -218,6 +368,62 @@ func (api *API) ValidCandidates(epoch uint64) (interface{}, error) {
 	return sys.GetState(gstate.PreEpoch)
 }
 
+// GetEpochSnapshot get the validators elected for epoch and their stakes
+func (api *API) GetEpochSnapshot(epoch uint64) (*EpochSnapshot, error) {
+	current, err := api.epoch(api.chain.CurrentHeader().Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	if epoch == 0 {
+		epoch = current
+	}
+	if epoch > current {
+		return nil, fmt.Errorf("epoch %v in the future, current %v", epoch, current)
+	}
+	sys, err := api.system()
+	if err != nil {
+		return nil, err
+	}
+	gstate, err := sys.GetState(epoch)
+	if err != nil {
+		return nil, err
+	}
+	elected, err := sys.GetState(gstate.PreEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	start := sys.config.epochTimeStamp(epoch)
+	if gstate.PreEpoch == gstate.Epoch {
+		start = sys.config.ReferenceTime
+	}
+	snapshot := &EpochSnapshot{
+		Epoch:                  epoch,
+		PreEpoch:               gstate.PreEpoch,
+		Start:                  start,
+		End:                    sys.config.epochTimeStamp(epoch + 1),
+		Dpos:                   elected.Dpos,
+		TakeOver:               gstate.TakeOver,
+		Validators:             make([]*CandidateInfo, 0, len(elected.ActivatedCandidateSchedule)),
+		ActivatedTotalQuantity: elected.ActivatedTotalQuantity,
+		TotalQuantity:          elected.TotalQuantity,
+	}
+	for _, name := range elected.ActivatedCandidateSchedule {
+		candidate, err := sys.GetCandidate(gstate.PreEpoch, name)
+		if err != nil {
+			return nil, err
+		}
+		if candidate == nil {
+			candidate = &CandidateInfo{Epoch: gstate.PreEpoch, Name: name}
+		}
+		snapshot.Validators = append(snapshot.Validators, candidate)
+	}
+	if err := api.fillStatus(sys, epoch, snapshot.Validators); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
 // BrowserAllEpoch get all epoch info for browser api
 func (api *API) BrowserAllEpoch() (interface{}, error) {
 	epochs := Epochs{}
@@ -632,6 +838,57 @@ func (api *API) epoch(number uint64) (uint64, error) {
 	return api.dpos.config.epoch(timestamp), nil
 }
 
+// fillStatus fill the total votes & producing status of candidates in epoch
+func (api *API) fillStatus(sys *System, epoch uint64, candidates []*CandidateInfo) error {
+	gstate, err := sys.GetState(epoch)
+	if err != nil {
+		return err
+	}
+	elected, err := sys.GetState(gstate.PreEpoch)
+	if err != nil {
+		return err
+	}
+	active := map[string]bool{}
+	if len(elected.UsingCandidateIndexSchedule) == 0 {
+		for index, name := range elected.ActivatedCandidateSchedule {
+			if uint64(index) >= sys.config.CandidateScheduleSize {
+				break
+			}
+			active[name] = true
+		}
+	} else {
+		for offset := range elected.UsingCandidateIndexSchedule {
+			if name := sys.usingCandiate(elected, uint64(offset)); len(name) != 0 {
+				active[name] = true
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+		candidate.TotalVotes = big.NewInt(0)
+		if candidate.TotalQuantity != nil {
+			candidate.TotalVotes.Mul(candidate.TotalQuantity, sys.config.unitStake())
+		}
+		candidate.Active = active[candidate.Name] && candidate.Type == Normal
+		switch {
+		case candidate.Type == Jail:
+			candidate.Status = "jailed"
+		case candidate.Type == Black:
+			candidate.Status = "kicked"
+		case candidate.Type == Freeze:
+			candidate.Status = "unregistered"
+		case candidate.Active:
+			candidate.Status = "active"
+		default:
+			candidate.Status = "standby"
+		}
+	}
+	return nil
+}
+
 func (api *API) system() (*System, error) {
 	state, err := api.chain.StateAt(api.chain.CurrentHeader().Root)
 	if err != nil {
@@ -692,3 +949,84 @@ func (api *API) GetActivedCandidate(epoch uint64, index uint64) (interface{}, er
 	ret["bad"] = isbad
 	return ret, nil
 }
+
+// MissedSlots notify the slots which candidate should produce but missed
+func (api *API) MissedSlots(ctx context.Context, candidate string) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		ch := make(chan *event.Event, 10)
+		sub := event.Subscribe(nil, ch, event.ChainHeadEv, &types.Block{})
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-ch:
+				block := ev.Data.(*types.Block)
+				missed, err := api.missedSlots(block.Header(), candidate)
+				if err != nil {
+					log.Debug("missed slots check failed", "number", block.NumberU64(), "err", err)
+					continue
+				}
+				for _, slot := range missed {
+					notifier.Notify(rpcSub.ID, slot)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+func (api *API) missedSlots(header *types.Header, candidate string) ([]*MissedSlot, error) {
+	if header.Number.Uint64() == 0 {
+		return nil, nil
+	}
+	parent := api.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("not found parent %v", header.ParentHash.Hex())
+	}
+	state, err := api.chain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := api.dpos.config
+	fid := header.CurForkID()
+	// only check one round of schedule when the chain halted for a long time
+	start := parent.Time.Uint64() + cfg.blockInterval()
+	if end := header.Time.Uint64(); end > cfg.mepochInterval() && start < end-cfg.mepochInterval() {
+		start = cfg.slot(end - cfg.mepochInterval())
+	}
+	missed := []*MissedSlot{}
+	for timestamp := start; timestamp <= header.Time.Uint64(); timestamp += cfg.blockInterval() {
+		expected, err := api.dpos.slotCandidate(parent, timestamp, state, fid)
+		if err == ErrSystemTakeOver {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		// the slots before the block are empty, the slot of the block is missed if another candidate produced it
+		if expected != candidate || (timestamp == header.Time.Uint64() && header.Coinbase.String() == candidate) {
+			continue
+		}
+		missed = append(missed, &MissedSlot{
+			Candidate: candidate,
+			Epoch:     cfg.epoch(timestamp),
+			Number:    header.Number.Uint64(),
+			Slot:      cfg.getoffset(timestamp, fid),
+			Timestamp: timestamp,
+		})
+	}
+	return missed, nil
+}
@@ -141,6 +141,9 @@ type CandidateInfo struct {
 	PrevKey       string        `json:"-"`
 	NextKey       string        `json:"-"`
 	PubKey        common.PubKey `json:"pubkey" rlp:"-"`
+	TotalVotes    *big.Int      `json:"totalVotes,omitempty" rlp:"-"` // total stake voted to candidate
+	Active        bool          `json:"active" rlp:"-"`               // in the producing schedule of the epoch
+	Status        string        `json:"status,omitempty" rlp:"-"`
 }
 
 func (candidateInfo *CandidateInfo) copy() *CandidateInfo {
@@ -256,6 +259,38 @@ type VoteEpoch struct {
 	Dpos  uint64 `json:"dpos"`
 }
 
+// ConsensusParams dpos slot timing & epoch length
+type ConsensusParams struct {
+	BlockInterval      uint64 `json:"blockInterval"`      // milliseconds between two blocks
+	BlockFrequency     uint64 `json:"blockFrequency"`     // blocks produced by a validator in one slot
+	SlotsPerEpoch      uint64 `json:"slotsPerEpoch"`      // validator slots in one epoch
+	EpochInterval      uint64 `json:"epochInterval"`      // milliseconds of one epoch
+	EpochLength        uint64 `json:"epochLength"`        // blocks in one epoch
+	ValidatorsPerEpoch uint64 `json:"validatorsPerEpoch"` // scheduled validators in one epoch
+}
+
+// MissedSlot slot which candidate should produce but not
+type MissedSlot struct {
+	Candidate string `json:"candidate"`
+	Epoch     uint64 `json:"epoch"`
+	Number    uint64 `json:"number"`    // number of the block which skip the slot
+	Slot      uint64 `json:"slot"`      // offset of the candidate in schedule
+	Timestamp uint64 `json:"timestamp"` // expected block time
+}
+
+// EpochSnapshot elected validators & stakes of an epoch
+type EpochSnapshot struct {
+	Epoch                  uint64           `json:"epoch"`
+	PreEpoch               uint64           `json:"preEpoch"`
+	Start                  uint64           `json:"start"` // epoch start timestamp
+	End                    uint64           `json:"end"`   // epoch end timestamp
+	Dpos                   bool             `json:"dpos"`
+	TakeOver               bool             `json:"takeOver"`
+	Validators             []*CandidateInfo `json:"validators"` // activated candidates in schedule order
+	ActivatedTotalQuantity *big.Int         `json:"activatedTotalQuantity"`
+	TotalQuantity          *big.Int         `json:"totalQuantity"`
+}
+
 func (prods CandidateInfoArray) Len() int {
 	return len(prods)
 }
@@ -812,10 +812,47 @@ func (dpos *Dpos) IsValidateCandidate(chain consensus.IChainReader, parent *type
 		}
 	}
 
-	pstate, err := sys.GetState(gstate.PreEpoch)
+	tname, err := dpos.scheduledCandidate(sys, parent, timestamp, gstate, fid)
 	if err != nil {
 		return err
 	}
+	if strings.Compare(tname, candidate) != 0 {
+		return fmt.Errorf("%v %v, except %v index %v", errInvalidBlockCandidate, candidate, tname, dpos.config.getoffset(timestamp, fid))
+	}
+
+	has := false
+	for _, pubkey := range pubkeys {
+		if sys.CanMine(candidate, pubkey) == nil {
+			has = true
+		}
+	}
+	if !has {
+		return ErrIllegalCandidatePubKey
+	}
+	return nil
+}
+
+// slotCandidate returns the candidate scheduled to produce the block at timestamp on top of parent.
+func (dpos *Dpos) slotCandidate(parent *types.Header, timestamp uint64, state *state.StateDB, fid uint64) (string, error) {
+	sys := NewSystem(state, dpos.config)
+	gstate, err := sys.GetState(dpos.config.epoch(parent.Time.Uint64()))
+	if err != nil {
+		return "", err
+	}
+	if gstate.TakeOver {
+		return "", ErrSystemTakeOver
+	}
+	return dpos.scheduledCandidate(sys, parent, timestamp, gstate, fid)
+}
+
+// scheduledCandidate returns the candidate of the schedule for the slot of timestamp,
+// gstate is the state of the epoch of parent.
+func (dpos *Dpos) scheduledCandidate(sys *System, parent *types.Header, timestamp uint64, gstate *GlobalState, fid uint64) (string, error) {
+	pepoch := dpos.config.epoch(parent.Time.Uint64())
+	pstate, err := sys.GetState(gstate.PreEpoch)
+	if err != nil {
+		return "", err
+	}
 
 	tname := ""
 	offset := dpos.config.getoffset(timestamp, fid)
@@ -827,7 +864,7 @@ func (dpos *Dpos) IsValidateCandidate(chain consensus.IChainReader, parent *type
 			n := sys.config.BackupScheduleSize + sys.config.CandidateScheduleSize
 			candidateInfoArray, err := sys.GetCandidates(pstate.Epoch)
 			if err != nil {
-				return err
+				return "", err
 			}
 			activatedCandidateSchedule := []string{}
 			activatedTotalQuantity := big.NewInt(0)
@@ -920,7 +957,7 @@ func (dpos *Dpos) IsValidateCandidate(chain consensus.IChainReader, parent *type
 						if !ok {
 							pcandidate, err := sys.GetCandidate(gstate.Epoch, pstate.ActivatedCandidateSchedule[cindex])
 							if err != nil {
-								return err
+								return "", err
 							}
 							tcandidate = pcandidate
 						}
@@ -928,7 +965,7 @@ func (dpos *Dpos) IsValidateCandidate(chain consensus.IChainReader, parent *type
 						if !ok {
 							pcandidate, err := sys.GetActivatedCandidate(uint64(offset))
 							if err != nil {
-								return err
+								return "", err
 							}
 							ptcandidate = pcandidate
 						}
@@ -951,7 +988,7 @@ func (dpos *Dpos) IsValidateCandidate(chain consensus.IChainReader, parent *type
 									log.Debug("replace discard...", "num", parent.Number.Uint64()+1, "epoch", pepoch, "mepoch", mepoch, "index", offset, "candiate", tcandidate.Name, "counter", tcandidate.Counter, "actual", tcandidate.ActualCounter)
 									rcandidate, err := sys.GetCandidate(gstate.Epoch, rname)
 									if err != nil {
-										return err
+										return "", err
 									}
 									tcandidate = rcandidate
 								} else {
@@ -976,7 +1013,7 @@ func (dpos *Dpos) IsValidateCandidate(chain consensus.IChainReader, parent *type
 				if !ok {
 					pcandidate, err := sys.GetCandidate(gstate.Epoch, name)
 					if err != nil {
-						return err
+						return "", err
 					}
 					candidates[coffset] = pcandidate
 					candidate = pcandidate
@@ -998,20 +1035,7 @@ func (dpos *Dpos) IsValidateCandidate(chain consensus.IChainReader, parent *type
 		}
 	}
 
-	if strings.Compare(tname, candidate) != 0 {
-		return fmt.Errorf("%v %v, except %v %v(%v) index %v (%v epoch) ", errInvalidBlockCandidate, candidate, tname, pstate.ActivatedCandidateSchedule, pstate.UsingCandidateIndexSchedule, offset, pstate.Epoch)
-	}
-
-	has := false
-	for _, pubkey := range pubkeys {
-		if sys.CanMine(candidate, pubkey) == nil {
-			has = true
-		}
-	}
-	if !has {
-		return ErrIllegalCandidatePubKey
-	}
-	return nil
+	return tname, nil
 }
 
 // BlockInterval block interval
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/fractalplatform/fractal/accountmanager"
 	"github.com/fractalplatform/fractal/common"
 	"github.com/fractalplatform/fractal/params"
 	"github.com/fractalplatform/fractal/state"
@@ -516,6 +517,16 @@ func (sys *System) RemoveKickedCandidate(epoch uint64, candidate string, number
 	return nil
 }
 
+// DestroyAccount destroy the account and transfer its balances to beneficiary,
+// a registered candidate can't be destroyed, otherwise its votes would be orphaned
+func (sys *System) DestroyAccount(epoch uint64, accountDB *accountmanager.AccountManager, name string, beneficiary string) error {
+	isCandidate := func(name common.Name) (bool, error) {
+		prod, err := sys.GetCandidate(epoch, name.String())
+		return prod != nil, err
+	}
+	return accountDB.DestroyAccount(common.StrToName(name), common.StrToName(beneficiary), isCandidate)
+}
+
 // ExitTakeOver system exit take over
 func (sys *System) ExitTakeOver(epoch uint64, number uint64, fid uint64) error {
 	gstate, err := sys.GetState(epoch)
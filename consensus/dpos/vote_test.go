@@ -21,6 +21,11 @@ import (
 	"math/big"
 	"strings"
 	"testing"
+
+	"github.com/fractalplatform/fractal/accountmanager"
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/rawdb"
+	"github.com/fractalplatform/fractal/state"
 )
 
 var (
@@ -284,3 +289,48 @@ func TestCandidateVote(t *testing.T) {
 		}
 	}
 }
+
+func TestDestroyCandidateAccount(t *testing.T) {
+	ldb, function := newTestLDB()
+	defer function()
+	db, err := NewLDB(ldb)
+	if err != nil {
+		t.Fatalf("create db failed --- %v", err)
+	}
+	sys := &System{
+		config: DefaultConfig,
+		IDB:    db,
+	}
+	candidate := candidates[0]
+	if err := db.SetState(&GlobalState{
+		Epoch:         0,
+		PreEpoch:      0,
+		TotalQuantity: big.NewInt(0),
+	}); err != nil {
+		t.Fatalf("SetState --- %v", err)
+	}
+	if err := sys.RegCandidate(0, candidate, fmt.Sprintf("www.%v.com", candidate), minStakeCandidate, 0, 0); err != nil {
+		t.Fatalf("RegCandidate --- %v", err)
+	}
+
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountDB, err := accountmanager.NewAccountManager(statedb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beneficiary := "a123456789aeee"
+	for _, name := range []string{candidate, beneficiary} {
+		if err := accountDB.CreateAccount(common.Name("fractal.founder"), common.StrToName(name), common.Name(""), 0, 0, common.PubKey{}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sys.DestroyAccount(0, accountDB, candidate, beneficiary); err == nil || !strings.Contains(err.Error(), "registered candidate") {
+		t.Errorf("DestroyAccount of candidate err = %v, want registered candidate", err)
+	}
+	if err := sys.DestroyAccount(0, accountDB, beneficiary, candidate); err != nil {
+		t.Errorf("DestroyAccount of non candidate err = %v", err)
+	}
+}
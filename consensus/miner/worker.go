@@ -348,12 +348,15 @@ func (worker *Worker) commitNewWork(timestamp int64, parent *types.Header, quit
 			log.BlockHash = block.Hash()
 		}
 
-		if _, err := worker.WriteBlockWithState(block, work.currentReceipts, work.currentState); err != nil {
+		isCanon, err := worker.WriteBlockWithState(block, work.currentReceipts, work.currentState)
+		if err != nil {
 			return nil, fmt.Errorf("writing block to chain, err: %v", err)
 		}
 		time.Sleep(time.Duration(worker.delayDuration * uint64(time.Millisecond)))
 
-		event.SendEvent(&event.Event{Typecode: event.ChainHeadEv, Data: block})
+		if isCanon {
+			event.SendEvent(&event.Event{Typecode: event.ChainHeadEv, Data: block})
+		}
 		event.SendEvent(&event.Event{Typecode: event.NewMinedEv, Data: blockchain.NewMinedBlockEvent{
 			Block: block,
 		}})
@@ -423,6 +426,9 @@ func (worker *Worker) commitTransactions(work *Work, txs *types.TransactionsByPr
 		work.currentState.Prepare(tx.Hash(), common.Hash{}, work.currentCnt)
 
 		logs, err := worker.commitTransaction(work, tx, endTime)
+		if nonceErr, ok := err.(*processor.NonceError); ok {
+			err = nonceErr.Err
+		}
 		switch err {
 		case vm.ErrExecOverTime:
 			log.Trace("Skipping transaction exec over time", "hash", tx.Hash())
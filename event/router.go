@@ -99,6 +99,7 @@ const (
 	OneMinuteLimited                               // 1029 add peer to blacklist
 	NewMinedEv                                     // 1030 emit when new block was mined
 	NewTxs                                         // 1031 emit when new transactions needed to broadcast
+	ChainReorgEv                                   // 1032 emit when the canonical chain was reorganised
 	EndSize
 )
 
@@ -18,9 +18,13 @@ package ftservice
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"sort"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/math"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/fractalplatform/fractal/accountmanager"
 	"github.com/fractalplatform/fractal/blockchain"
 	"github.com/fractalplatform/fractal/common"
@@ -44,6 +48,16 @@ import (
 type APIBackend struct {
 	ftservice *FtService
 	gpo       *gasprice.Oracle
+	tdCache   *lru.Cache // total difficulties by block hash, they never change once written
+}
+
+func newAPIBackend(ftservice *FtService) *APIBackend {
+	size := ftservice.config.RPCTdCacheSize
+	if size <= 0 {
+		size = DefaultRPCTdCacheSize
+	}
+	tdCache, _ := lru.New(size)
+	return &APIBackend{ftservice: ftservice, tdCache: tdCache}
 }
 
 // ChainConfig returns the active chain configuration.
@@ -74,6 +88,36 @@ func (b *APIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) er
 	return b.ftservice.txPool.AddLocal(signedTx)
 }
 
+// GetPoolTransactions returns the pending transactions of the pool, sorted
+// by sender account name and nonce.
+func (b *APIBackend) GetPoolTransactions() ([]*types.Transaction, error) {
+	pending, err := b.ftservice.txPool.Pending()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name.String())
+	}
+	sort.Strings(names)
+	var txs []*types.Transaction
+	for _, name := range names {
+		txs = append(txs, pending[common.Name(name)]...)
+	}
+	return txs, nil
+}
+
+// TxPoolStats returns the number of pending and queued transactions of the pool.
+func (b *APIBackend) TxPoolStats() (pending int, queued int) {
+	return b.ftservice.txPool.Stats()
+}
+
+// TxPoolContent returns the pending and queued transactions of the pool,
+// grouped by sender account and sorted by nonce.
+func (b *APIBackend) TxPoolContent() (map[common.Name][]*types.Transaction, map[common.Name][]*types.Transaction) {
+	return b.ftservice.txPool.Content()
+}
+
 func (b *APIBackend) TxPool() *txpool.TxPool {
 	return b.ftservice.TxPool()
 }
@@ -117,31 +161,60 @@ func (b *APIBackend) GetBlockDetailLog(ctx context.Context, blockNr rpc.BlockNum
 	}
 }
 
-func (b *APIBackend) GetTxsByFilter(ctx context.Context, filterFn func(common.Name) bool, blockNr, lookforwardNum uint64) *types.AccountTxs {
-	if lookforwardNum > 128 {
-		lookforwardNum = 128
+// GetTxsByFilter scans blocks from blockNr to blockNr+lookforwardNum for txs
+// matched by filterFn. bloomFn reports whether a name bloom may contain the
+// matched names, it is used to skip blocks by the name bloom index, blocks
+// before the index was built are scanned one by one.
+func (b *APIBackend) GetTxsByFilter(ctx context.Context, filterFn func(common.Name) bool, bloomFn func(types.Bloom) bool, blockNr, lookforwardNum uint64) (*types.AccountTxs, error) {
+	if max := b.MaxLookback(); lookforwardNum > max {
+		return nil, fmt.Errorf("lookforwardNum %d exceeds the limit %d, please page the request", lookforwardNum, max)
+	}
+
+	var tail *uint64
+	if bloomFn != nil {
+		tail = rawdb.ReadNameBloomTail(b.ftservice.chainDb)
 	}
+	checkedSection := int64(-1)
 
 	lastnum := int64(blockNr + lookforwardNum)
 	txhhpairs := make([]*types.TxHeightHashPair, 0)
 	for ublocknum := int64(blockNr); ublocknum <= lastnum; ublocknum++ {
+		if tail != nil {
+			if section := ublocknum / rawdb.NameBloomSectionSize; section != checkedSection {
+				checkedSection = section
+				if uint64(section*rawdb.NameBloomSectionSize) >= *tail {
+					if bloom := rawdb.ReadNameBloomSection(b.ftservice.chainDb, uint64(section)); bloom != nil && !bloomFn(*bloom) {
+						ublocknum = (section+1)*rawdb.NameBloomSectionSize - 1
+						continue
+					}
+				}
+			}
+		}
+
 		hash := rawdb.ReadCanonicalHash(b.ftservice.chainDb, uint64(ublocknum))
 		if hash == (common.Hash{}) {
 			continue
 		}
 
+		if tail != nil {
+			if bloom := rawdb.ReadNameBloom(b.ftservice.chainDb, hash, uint64(ublocknum)); bloom != nil && !bloomFn(*bloom) {
+				continue
+			}
+		}
+
 		blockBody := rawdb.ReadBody(b.ftservice.chainDb, hash, uint64(ublocknum))
 		if blockBody == nil {
 			continue
 		}
 		batchTxs := blockBody.Transactions
 
-		for _, tx := range batchTxs {
+		for i, tx := range batchTxs {
 			for _, act := range tx.GetActions() {
 				if filterFn(act.Sender()) || filterFn(act.Recipient()) {
 					hhpair := &types.TxHeightHashPair{
 						Hash:   tx.Hash(),
 						Height: uint64(ublocknum),
+						Index:  uint64(i),
 					}
 					txhhpairs = append(txhhpairs, hhpair)
 					break
@@ -156,10 +229,16 @@ func (b *APIBackend) GetTxsByFilter(ctx context.Context, filterFn func(common.Na
 		EndHeight:               uint64(lastnum),
 	}
 
-	return accountTxs
+	return accountTxs, nil
 }
 
-func (b *APIBackend) GetDetailTxByFilter(ctx context.Context, filterFn func(common.Name) bool, blockNr, lookbackNum uint64) []*types.DetailTx {
+// GetDetailTxByFilter returns the internal txs matching filterFn from blockNr back to
+// blockNr-lookbackNum, skipping the first offset matches. A non-zero limit stops the
+// scan once limit txs are collected, the bool result reports whether more matches exist.
+func (b *APIBackend) GetDetailTxByFilter(ctx context.Context, filterFn func(common.Name) bool, blockNr, lookbackNum, offset, limit uint64) ([]*types.DetailTx, bool, error) {
+	if max := b.MaxLookback(); lookbackNum > max {
+		return nil, false, fmt.Errorf("lookbackNum %d exceeds the limit %d, please page the request", lookbackNum, max)
+	}
 	var lastnum int64
 	if lookbackNum > blockNr {
 		lastnum = 0
@@ -167,6 +246,7 @@ func (b *APIBackend) GetDetailTxByFilter(ctx context.Context, filterFn func(comm
 		lastnum = int64(blockNr - lookbackNum)
 	}
 	txdetails := make([]*types.DetailTx, 0)
+	var matched uint64
 
 	for ublocknum := int64(blockNr); ublocknum >= lastnum; ublocknum-- {
 		hash := rawdb.ReadCanonicalHash(b.ftservice.chainDb, uint64(ublocknum))
@@ -189,21 +269,47 @@ func (b *APIBackend) GetDetailTxByFilter(ctx context.Context, filterFn func(comm
 				}
 			}
 
-			if len(newIntxs) > 0 {
+			if len(newIntxs) == 0 {
+				continue
+			}
+			if limit > 0 && matched == offset+limit {
+				return txdetails, true, nil
+			}
+			if matched >= offset {
 				txdetails = append(txdetails, &types.DetailTx{TxHash: txd.TxHash, Actions: newIntxs})
 			}
+			matched++
 		}
 	}
 
-	return txdetails
+	return txdetails, false, nil
 }
 
-func (b *APIBackend) GetBadBlocks(ctx context.Context) ([]*types.Block, error) {
+func (b *APIBackend) GetBadBlocks(ctx context.Context) ([]*blockchain.BadBlock, error) {
 	return b.ftservice.blockchain.BadBlocks(), nil
 }
 
+func (b *APIBackend) GetBadBlock(ctx context.Context, hash common.Hash) *blockchain.BadBlock {
+	return b.ftservice.blockchain.BadBlock(hash)
+}
+
+func (b *APIBackend) RemoveBadBlock(ctx context.Context, hash common.Hash) bool {
+	return b.ftservice.blockchain.RemoveBadBlock(hash)
+}
+
+func (b *APIBackend) ReprocessBlock(ctx context.Context, block *types.Block, vmCfg vm.Config) ([]*types.Receipt, error) {
+	return b.ftservice.blockchain.ReprocessBlock(block, vmCfg)
+}
+
 func (b *APIBackend) GetTd(blockHash common.Hash) *big.Int {
-	return b.ftservice.blockchain.GetTdByHash(blockHash)
+	if td, ok := b.tdCache.Get(blockHash); ok {
+		return td.(*big.Int)
+	}
+	td := b.ftservice.blockchain.GetTdByHash(blockHash)
+	if td != nil {
+		b.tdCache.Add(blockHash, td)
+	}
+	return td
 }
 
 func (b *APIBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) *types.Header {
@@ -229,10 +335,24 @@ func (b *APIBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.Blo
 	if header == nil {
 		return nil, nil, nil
 	}
-	stateDb, err := b.ftservice.blockchain.StateAt(b.ftservice.blockchain.CurrentBlock().Root())
+	stateDb, err := b.ftservice.blockchain.StateAt(header.Root)
+	return stateDb, header, err
+}
+
+func (b *APIBackend) StateAndHeaderByHash(ctx context.Context, hash common.Hash) (*state.StateDB, *types.Header, error) {
+	header := b.HeaderByHash(ctx, hash)
+	if header == nil {
+		return nil, nil, nil
+	}
+	stateDb, err := b.ftservice.blockchain.StateAt(header.Root)
 	return stateDb, header, err
 }
 
+// OldestStateNumber returns the number of the oldest block whose state is kept.
+func (b *APIBackend) OldestStateNumber() uint64 {
+	return b.ftservice.blockchain.OldestStateNumber()
+}
+
 func (b *APIBackend) GetEVM(ctx context.Context, account *accountmanager.AccountManager, state *state.StateDB, from common.Name, to common.Name, assetID uint64, gasPrice *big.Int, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error) {
 	account.AddAccountBalanceByID(from, assetID, math.MaxBig256)
 	vmError := func() error { return nil }
@@ -246,6 +366,64 @@ func (b *APIBackend) GetEVM(ctx context.Context, account *accountmanager.Account
 	return vm.NewEVM(context, account, state, b.ChainConfig(), vmCfg), vmError, nil
 }
 
+// ReplayTransaction re-executes the txs of block before txIndex on the parent
+// state, then applies the tx at txIndex with vmCfg and returns its receipt.
+func (b *APIBackend) ReplayTransaction(ctx context.Context, block *types.Block, txIndex int, vmCfg vm.Config) (*types.Receipt, error) {
+	txs := block.Transactions()
+	if txIndex < 0 || txIndex >= len(txs) {
+		return nil, fmt.Errorf("tx index %d out of range", txIndex)
+	}
+	bc := b.ftservice.blockchain
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %x not found", block.ParentHash())
+	}
+	statedb, err := bc.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		header  = block.Header()
+		gp      = new(common.GasPool).AddGas(block.GasLimit())
+		usedGas uint64
+	)
+	b.ftservice.engine.Prepare(bc, header, txs, nil, statedb)
+	for i, tx := range txs[:txIndex] {
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		if _, _, err := bc.Processor().ApplyTransaction(nil, gp, statedb, header, tx, &usedGas, vm.Config{}); err != nil {
+			return nil, err
+		}
+	}
+	statedb.Prepare(txs[txIndex].Hash(), block.Hash(), txIndex)
+	receipt, _, err := bc.Processor().ApplyTransaction(nil, gp, statedb, header, txs[txIndex], &usedGas, vmCfg)
+	return receipt, err
+}
+
+// CallTimeout returns the evm execution timeout of a call.
+func (b *APIBackend) CallTimeout() time.Duration {
+	if timeout := b.ftservice.config.RPCCallTimeout; timeout > 0 {
+		return timeout
+	}
+	return DefaultRPCCallTimeout
+}
+
+// EstimateGasTimeout returns the total evm execution timeout of a gas estimation.
+func (b *APIBackend) EstimateGasTimeout() time.Duration {
+	if timeout := b.ftservice.config.RPCEstimateGasTimeout; timeout > 0 {
+		return timeout
+	}
+	return DefaultRPCEstimateGasTimeout
+}
+
+// MaxLookback returns the max number of blocks scanned by a tx filter request.
+func (b *APIBackend) MaxLookback() uint64 {
+	if max := b.ftservice.config.RPCMaxLookback; max > 0 {
+		return max
+	}
+	return DefaultRPCMaxLookback
+}
+
 func (b *APIBackend) SetGasPrice(gasPrice *big.Int) bool {
 	return b.ftservice.SetGasPrice(gasPrice)
 }
@@ -0,0 +1,154 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package ftservice
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/consensus/dpos"
+	"github.com/fractalplatform/fractal/rawdb"
+	"github.com/fractalplatform/fractal/types"
+)
+
+// newBloomTestBackend writes a canonical chain of n+1 blocks, every block has a
+// tx between "testsender" and "testreceiver", the blocks in matched also have a
+// tx between "alice" and "bob". Name blooms are written from block tail on.
+func newBloomTestBackend(n, tail uint64, matched map[uint64]bool) *APIBackend {
+	db := rawdb.NewMemoryDatabase()
+	var parent common.Hash
+	for i := uint64(0); i <= n; i++ {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(i),
+			Difficulty: big.NewInt(1),
+		}
+		action := types.NewAction(types.Transfer, common.Name("testsender"), common.Name("testreceiver"), 0, 0, 0, big.NewInt(0), nil, nil)
+		txs := []*types.Transaction{types.NewTransaction(0, big.NewInt(1), action)}
+		if matched[i] {
+			action := types.NewAction(types.Transfer, common.Name("alice"), common.Name("bob"), 0, 0, 0, big.NewInt(0), nil, nil)
+			txs = append(txs, types.NewTransaction(0, big.NewInt(1), action))
+		}
+		block := types.NewBlockWithHeader(header).WithBody(txs)
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), i)
+
+		if i >= tail {
+			bloom := types.CreateNameBloom(txs)
+			rawdb.WriteNameBloom(db, block.Hash(), i, bloom)
+			section := i / rawdb.NameBloomSectionSize
+			if sbloom := rawdb.ReadNameBloomSection(db, section); sbloom != nil {
+				bloom = types.BytesToBloom(new(big.Int).Or(sbloom.Big(), bloom.Big()).Bytes())
+			}
+			rawdb.WriteNameBloomSection(db, section, bloom)
+		}
+		parent = block.Hash()
+	}
+	rawdb.WriteNameBloomTail(db, tail)
+
+	ftservice := &FtService{
+		config:  &Config{RPCMaxLookback: 1000},
+		chainDb: db,
+		engine:  dpos.New(dpos.DefaultConfig, nil),
+	}
+	return newAPIBackend(ftservice)
+}
+
+func TestGetTxsByFilterNameBloom(t *testing.T) {
+	var (
+		size    = uint64(rawdb.NameBloomSectionSize)
+		tail    = size / 2
+		matched = map[uint64]bool{
+			0:            true,
+			tail - 1:     true,
+			tail:         true,
+			size - 1:     true,
+			size:         true,
+			3 * size:     true,
+			3*size + 1:   true,
+			3*size + 100: true,
+		}
+		b = newBloomTestBackend(4*size, tail, matched)
+	)
+	filterFn := func(name common.Name) bool { return name == common.Name("alice") }
+	bloomFn := func(bloom types.Bloom) bool { return bloom.TestBytes([]byte("alice")) }
+
+	heights := func(from, num uint64) []uint64 {
+		accountTxs, err := b.GetTxsByFilter(context.Background(), filterFn, bloomFn, from, num)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accountTxs.EndHeight != from+num {
+			t.Fatalf("end height mismatch: have %d, want %d", accountTxs.EndHeight, from+num)
+		}
+		var res []uint64
+		for _, pair := range accountTxs.Txs {
+			if pair.Index != 1 {
+				t.Fatalf("block %d: matched tx index %d, want 1", pair.Height, pair.Index)
+			}
+			res = append(res, pair.Height)
+		}
+		return res
+	}
+
+	tests := []struct {
+		from, num uint64
+		want      []uint64
+	}{
+		// blocks below the tail have no bloom and are scanned one by one
+		{0, tail, []uint64{0, tail - 1, tail}},
+		// the section holding the tail is never skipped, only its blocks from the tail on have blooms
+		{tail - 1, size, []uint64{tail - 1, tail, size - 1, size}},
+		// the empty section is skipped, the scan resumes at the next section
+		{size + 1, 2 * size, []uint64{3 * size, 3*size + 1}},
+		// a scan starting in the middle of a section
+		{3*size + 2, size, []uint64{3*size + 100}},
+		// the whole chain
+		{0, 4 * size, []uint64{0, tail - 1, tail, size - 1, size, 3 * size, 3*size + 1, 3*size + 100}},
+		// beyond the head
+		{4*size + 1, size, nil},
+	}
+	for i, test := range tests {
+		if have := heights(test.from, test.num); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("test %d: heights mismatch: have %v, want %v", i, have, test.want)
+		}
+	}
+
+	// without bloomFn every block is scanned
+	accountTxs, err := b.GetTxsByFilter(context.Background(), filterFn, nil, 0, 4*size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accountTxs.Txs) != len(matched) {
+		t.Errorf("full scan: have %d txs, want %d", len(accountTxs.Txs), len(matched))
+	}
+
+	// the section blooms are trusted from the tail on, the section holding the
+	// tail is checked block by block
+	rawdb.WriteNameBloomSection(b.ftservice.chainDb, 0, types.Bloom{})
+	rawdb.WriteNameBloomSection(b.ftservice.chainDb, 3, types.Bloom{})
+	if have, want := heights(0, 4*size), []uint64{0, tail - 1, tail, size - 1, size}; !reflect.DeepEqual(have, want) {
+		t.Errorf("emptied sections: heights mismatch: have %v, want %v", have, want)
+	}
+
+	if _, err := b.GetTxsByFilter(context.Background(), filterFn, bloomFn, 0, b.MaxLookback()+1); err == nil {
+		t.Error("expected an error for lookforwardNum beyond the max lookback")
+	}
+}
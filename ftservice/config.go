@@ -17,6 +17,8 @@
 package ftservice
 
 import (
+	"time"
+
 	"github.com/fractalplatform/fractal/blockchain"
 	"github.com/fractalplatform/fractal/ftservice/gasprice"
 	"github.com/fractalplatform/fractal/metrics"
@@ -49,8 +51,29 @@ type Config struct {
 
 	BadHashes   []string `mapstructure:"badhashes"`
 	StartNumber uint64   `mapstructure:"startnumber"`
+
+	// RPC evm execution timeouts
+	RPCCallTimeout        time.Duration `mapstructure:"rpccalltimeout"`
+	RPCEstimateGasTimeout time.Duration `mapstructure:"rpcestimategastimeout"`
+
+	// RPCMaxLookback is the max number of blocks scanned by a tx filter request
+	RPCMaxLookback uint64 `mapstructure:"rpcmaxlookback"`
+
+	// RPCTdCacheSize is the number of block total difficulties cached for rpc requests
+	RPCTdCacheSize int `mapstructure:"rpctdcachesize"`
 }
 
+var (
+	// DefaultRPCCallTimeout is the evm execution timeout of a call if not configured.
+	DefaultRPCCallTimeout = 5 * time.Second
+	// DefaultRPCEstimateGasTimeout is the total evm execution timeout of a gas estimation if not configured.
+	DefaultRPCEstimateGasTimeout = 30 * time.Second
+	// DefaultRPCMaxLookback is the max number of blocks scanned by a tx filter request if not configured.
+	DefaultRPCMaxLookback uint64 = 10000
+	// DefaultRPCTdCacheSize is the number of block total difficulties cached for rpc requests if not configured.
+	DefaultRPCTdCacheSize = 4096
+)
+
 // MinerConfig miner config
 type MinerConfig struct {
 	Start       bool     `mapstructure:"start"`
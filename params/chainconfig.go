@@ -158,3 +158,12 @@ const (
 	// NextForkID is the id of next fork
 	NextForkID uint64 = ForkID4
 )
+
+// ForkNames describe the upgrade of each fork id.
+var ForkNames = map[uint64]string{
+	ForkID0: "init",
+	ForkID1: "account first name > 12, asset name contain account name",
+	ForkID2: "dpos",
+	ForkID3: "dpos config candidateAvailableMinQuantity modified",
+	ForkID4: "miner pubkey separate",
+}
@@ -68,3 +68,27 @@ type GenesisMismatchError struct {
 func (e *GenesisMismatchError) Error() string {
 	return fmt.Sprintf("database already contains an incompatible genesis block (have %x, new %x)", e.Stored[:8], e.New[:8])
 }
+
+// NonceError is returned if the nonce of an action differs from the nonce of
+// the sender, it wraps ErrNonceTooHigh or ErrNonceTooLow.
+type NonceError struct {
+	Account  common.Name
+	Expected uint64
+	Actual   uint64
+	Err      error
+}
+
+func (e *NonceError) Error() string { return e.Err.Error() }
+
+// Unwrap returns ErrNonceTooHigh or ErrNonceTooLow.
+func (e *NonceError) Unwrap() error { return e.Err }
+
+// CompareNonce compares the nonce of an action with the expected nonce of the account.
+func CompareNonce(account common.Name, expected, actual uint64) error {
+	if expected < actual {
+		return &NonceError{Account: account, Expected: expected, Actual: actual, Err: ErrNonceTooHigh}
+	} else if expected > actual {
+		return &NonceError{Account: account, Expected: expected, Actual: actual, Err: ErrNonceTooLow}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/fractalplatform/fractal/common"
+)
+
+func TestCompareNonce(t *testing.T) {
+	account := common.Name("testaccount")
+	if err := CompareNonce(account, 5, 5); err != nil {
+		t.Errorf("equal nonce err = %v", err)
+	}
+
+	tests := []struct {
+		expected, actual uint64
+		want             error
+	}{
+		{5, 6, ErrNonceTooHigh},
+		{5, 4, ErrNonceTooLow},
+	}
+	for _, test := range tests {
+		err := CompareNonce(account, test.expected, test.actual)
+		nonceErr, ok := err.(*NonceError)
+		if !ok || nonceErr.Err != test.want {
+			t.Fatalf("CompareNonce(%d, %d) = %v, want %v", test.expected, test.actual, err, test.want)
+		}
+		if err.Error() != test.want.Error() {
+			t.Errorf("CompareNonce message %q, want %q", err.Error(), test.want.Error())
+		}
+		if nonceErr.Account != account || nonceErr.Expected != test.expected || nonceErr.Actual != test.actual {
+			t.Errorf("CompareNonce(%d, %d) = %+v, want NonceError", test.expected, test.actual, err)
+		}
+	}
+}
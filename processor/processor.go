@@ -119,10 +119,8 @@ func (p *StateProcessor) ApplyTransaction(author *common.Name, gp *common.GasPoo
 		if err != nil {
 			return nil, 0, err
 		}
-		if nonce < action.Nonce() {
-			return nil, 0, ErrNonceTooHigh
-		} else if nonce > action.Nonce() {
-			return nil, 0, ErrNonceTooLow
+		if err := CompareNonce(action.Sender(), nonce, action.Nonce()); err != nil {
+			return nil, 0, err
 		}
 
 		var gasPayer = action.Sender()
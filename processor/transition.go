@@ -212,12 +212,7 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 			return nil, 0, false, vmerr, vmerr
 		}
 	}
-	nonce, err := st.account.GetNonce(st.from)
-	if err != nil {
-		return nil, st.gasUsed(), true, err, vmerr
-	}
-	err = st.account.SetNonce(st.from, nonce+1)
-	if err != nil {
+	if err := st.account.AddNonce(st.from, 1); err != nil {
 		return nil, st.gasUsed(), true, err, vmerr
 	}
 	st.refundGas()
@@ -28,4 +28,5 @@ var (
 	ErrContractCodeCollision    = errors.New("contract code collision")
 	ErrAccountNotExist          = errors.New("account not exist")
 	ErrExecOverTime             = errors.New("vm exec overtime shutdown")
+	ErrExecutionReverted        = errors.New("evm: execution reverted")
 )
@@ -360,6 +360,11 @@ func (evm *EVM) Call(caller ContractRef, action *types.Action, gas uint64) (ret
 	code, _ := acct.GetCode()
 	contract.SetCallCode(&toName, codeHash, code)
 
+	if evm.vmConfig.Debug && evm.depth == 0 {
+		evm.vmConfig.Tracer.CaptureStart(caller.Name(), toName, true, action.Data(), gas, action.Value())
+	}
+	start := time.Now()
+
 	ret, err = run(evm, contract, action.Data())
 	runGas := gas - contract.Gas
 
@@ -376,10 +381,13 @@ func (evm *EVM) Call(caller ContractRef, action *types.Action, gas uint64) (ret
 	// when we're in homestead this also counts for code storage gas errors.
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
+	if evm.vmConfig.Debug && evm.depth == 0 {
+		evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
+	}
 	actualUsedGas := gas - contract.Gas
 	evm.distributeGasByScale(actualUsedGas, runGas)
 	return ret, contract.Gas, err
@@ -438,7 +446,7 @@ func (evm *EVM) CallCode(caller ContractRef, action *types.Action, gas uint64) (
 
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
@@ -491,7 +499,7 @@ func (evm *EVM) DelegateCall(caller ContractRef, name common.Name, input []byte,
 
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
@@ -554,7 +562,7 @@ func (evm *EVM) StaticCall(caller ContractRef, name common.Name, input []byte, g
 
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
@@ -637,7 +645,7 @@ func (evm *EVM) Create(caller ContractRef, action *types.Action, gas uint64) (re
 	// when we're in homestead this also counts for code storage gas errors.
 	if maxCodeSizeExceeded || (err != nil && err != ErrCodeStoreOutOfGas) {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
@@ -690,7 +698,7 @@ func (evm *EVM) CanTransferContractAsset(caller ContractRef, gas uint64, assetID
 	// when we're in homestead this also counts for code storage gas errors.
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
-		if err != errExecutionReverted {
+		if err != ErrExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	}
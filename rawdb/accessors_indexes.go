@@ -17,6 +17,8 @@
 package rawdb
 
 import (
+	"encoding/binary"
+
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/fractalplatform/fractal/common"
 	"github.com/fractalplatform/fractal/types"
@@ -105,3 +107,57 @@ func WriteBloomBits(db DatabaseWriter, bit uint, section uint64, head common.Has
 		log.Crit("Failed to store bloom bits", "err", err)
 	}
 }
+
+// NameBloomSectionSize is the number of blocks in a name bloom section.
+const NameBloomSectionSize = 128
+
+// ReadNameBloom retrieves the bloom of account names touched by the txs of a block.
+func ReadNameBloom(db DatabaseReader, hash common.Hash, number uint64) *types.Bloom {
+	data, _ := db.Get(nameBloomKey(number, hash))
+	if len(data) != types.BloomByteLength {
+		return nil
+	}
+	bloom := types.BytesToBloom(data)
+	return &bloom
+}
+
+// WriteNameBloom stores the bloom of account names touched by the txs of a block.
+func WriteNameBloom(db DatabaseWriter, hash common.Hash, number uint64, bloom types.Bloom) {
+	if err := db.Put(nameBloomKey(number, hash), bloom.Bytes()); err != nil {
+		log.Crit("Failed to store name bloom", "err", err)
+	}
+}
+
+// ReadNameBloomSection retrieves the merged name bloom of all blocks in a section.
+func ReadNameBloomSection(db DatabaseReader, section uint64) *types.Bloom {
+	data, _ := db.Get(nameBloomSectionKey(section))
+	if len(data) != types.BloomByteLength {
+		return nil
+	}
+	bloom := types.BytesToBloom(data)
+	return &bloom
+}
+
+// WriteNameBloomSection stores the merged name bloom of all blocks in a section.
+func WriteNameBloomSection(db DatabaseWriter, section uint64, bloom types.Bloom) {
+	if err := db.Put(nameBloomSectionKey(section), bloom.Bytes()); err != nil {
+		log.Crit("Failed to store name bloom section", "err", err)
+	}
+}
+
+// ReadNameBloomTail retrieves the first block number indexed with name bloom.
+func ReadNameBloomTail(db DatabaseReader) *uint64 {
+	data, _ := db.Get(nameBloomTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteNameBloomTail stores the first block number indexed with name bloom.
+func WriteNameBloomTail(db DatabaseWriter, number uint64) {
+	if err := db.Put(nameBloomTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store name bloom tail", "err", err)
+	}
+}
@@ -73,3 +73,42 @@ func TestLookupStorage(t *testing.T) {
 		}
 	}
 }
+
+// Tests that name bloom index can be stored and retrieved.
+func TestNameBloomStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	action := types.NewAction(types.Transfer, common.Name("fromtest"), common.Name("tototest"), uint64(3), uint64(3), uint64(2000), big.NewInt(1000), []byte("test action"), []byte("test remark"))
+	block := &types.Block{
+		Head: &types.Header{Number: big.NewInt(314), Coinbase: "coinbase"},
+		Txs:  []*types.Transaction{types.NewTransaction(uint64(1), big.NewInt(1), action)},
+	}
+
+	if bloom := ReadNameBloom(db, block.Hash(), block.NumberU64()); bloom != nil {
+		t.Fatalf("non existent name bloom returned: %x", bloom)
+	}
+	if tail := ReadNameBloomTail(db); tail != nil {
+		t.Fatalf("non existent name bloom tail returned: %d", *tail)
+	}
+
+	WriteNameBloom(db, block.Hash(), block.NumberU64(), types.CreateNameBloom(block.Txs))
+	WriteNameBloomSection(db, block.NumberU64()/NameBloomSectionSize, types.CreateNameBloom(block.Txs))
+	WriteNameBloomTail(db, block.NumberU64())
+
+	bloom := ReadNameBloom(db, block.Hash(), block.NumberU64())
+	if bloom == nil {
+		t.Fatal("name bloom not found")
+	}
+	section := ReadNameBloomSection(db, block.NumberU64()/NameBloomSectionSize)
+	if section == nil {
+		t.Fatal("name bloom section not found")
+	}
+	for _, name := range []string{"fromtest", "tototest"} {
+		if !bloom.TestBytes([]byte(name)) || !section.TestBytes([]byte(name)) {
+			t.Fatalf("name %s not in bloom", name)
+		}
+	}
+	if tail := ReadNameBloomTail(db); tail == nil || *tail != block.NumberU64() {
+		t.Fatalf("name bloom tail mismatch: have %v, want %d", tail, block.NumberU64())
+	}
+}
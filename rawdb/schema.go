@@ -45,12 +45,17 @@ var (
 
 	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	nameBloomPrefix = []byte("N") // nameBloomPrefix + num (uint64 big endian) + hash -> name bloom of block txs
 
 	preimagePrefix = []byte("secure-key-") // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ft-config-")  // config prefix for the db
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
-	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	BloomBitsIndexPrefix   = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	nameBloomSectionPrefix = []byte("iN") // nameBloomSectionPrefix + section (uint64 big endian) -> name bloom of section blocks
+
+	// nameBloomTailKey tracks the first block number with name bloom.
+	nameBloomTailKey = []byte("NameBloomTail")
 
 	blockStateOutPrefix = []byte("S") // blockRevertPrefix + num (uint64 big endian) + hash -> block revert info
 
@@ -146,3 +151,13 @@ func preimageKey(hash common.Hash) []byte {
 func configKey(hash common.Hash) []byte {
 	return append(configPrefix, hash.Bytes()...)
 }
+
+// nameBloomKey = nameBloomPrefix + num (uint64 big endian) + hash
+func nameBloomKey(number uint64, hash common.Hash) []byte {
+	return append(append(nameBloomPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// nameBloomSectionKey = nameBloomSectionPrefix + section (uint64 big endian)
+func nameBloomSectionKey(section uint64) []byte {
+	return append(nameBloomSectionPrefix, encodeBlockNumber(section)...)
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package rpcapi
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/processor/vm"
+	"github.com/fractalplatform/fractal/rpc"
+)
+
+// AccessTuple is an account and the storage keys of it touched by an action.
+type AccessTuple struct {
+	Account     common.Name   `json:"account"`
+	StorageKeys []common.Hash `json:"storageKeys"`
+}
+
+// AccessListResult is the result of CreateAccessList.
+type AccessListResult struct {
+	AccessList []AccessTuple  `json:"accessList"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// accessListTracer records every account and storage slot accessed by the evm.
+type accessListTracer struct {
+	list map[common.Name]map[common.Hash]struct{}
+}
+
+func newAccessListTracer(accounts ...common.Name) *accessListTracer {
+	t := &accessListTracer{list: make(map[common.Name]map[common.Hash]struct{})}
+	for _, name := range accounts {
+		t.addAccount(name)
+	}
+	return t
+}
+
+func (t *accessListTracer) addAccount(name common.Name) {
+	if _, ok := t.list[name]; !ok {
+		t.list[name] = make(map[common.Hash]struct{})
+	}
+}
+
+func (t *accessListTracer) CaptureStart(from common.Name, to common.Name, call bool, input []byte, gas uint64, value *big.Int) error {
+	t.addAccount(from)
+	t.addAccount(to)
+	return nil
+}
+
+func (t *accessListTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	name := contract.Name()
+	t.addAccount(name)
+	if (op == vm.SLOAD || op == vm.SSTORE) && len(stack.Data()) >= 1 {
+		t.list[name][common.BigToHash(stack.Back(0))] = struct{}{}
+	}
+	return nil
+}
+
+func (t *accessListTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *accessListTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// accessList returns the recorded accounts and keys in a deterministic order.
+func (t *accessListTracer) accessList() []AccessTuple {
+	list := make([]AccessTuple, 0, len(t.list))
+	for name, keys := range t.list {
+		tuple := AccessTuple{Account: name, StorageKeys: make([]common.Hash, 0, len(keys))}
+		for key := range keys {
+			tuple.StorageKeys = append(tuple.StorageKeys, key)
+		}
+		sort.Slice(tuple.StorageKeys, func(i, j int) bool { return tuple.StorageKeys[i].Big().Cmp(tuple.StorageKeys[j].Big()) < 0 })
+		list = append(list, tuple)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Account < list[j].Account })
+	return list
+}
+
+// CreateAccessList returns the accounts and storage keys the action touches when
+// executed on the state of the given block, with the gas used and the error of
+// the execution. Actions don't carry an access list, so a single execution
+// records all of them.
+func (s *PublicBlockChainAPI) CreateAccessList(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (result *AccessListResult, err error) {
+	defer timeMethod("createAccessList", &err)()
+	tracer := newAccessListTracer(args.From, args.To)
+	res, err := s.doCall(ctx, args, blockNr, nil, vm.Config{Debug: true, Tracer: tracer}, s.b.CallTimeout())
+	if err != nil {
+		return nil, err
+	}
+	result = &AccessListResult{AccessList: tracer.accessList(), GasUsed: hexutil.Uint64(res.UsedGas)}
+	if res.Failed() {
+		result.Error = res.Err.Error()
+	}
+	return result, nil
+}
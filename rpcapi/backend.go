@@ -21,6 +21,7 @@ package rpcapi
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/fractalplatform/fractal/accountmanager"
 	"github.com/fractalplatform/fractal/blockchain"
@@ -51,21 +52,33 @@ type Backend interface {
 	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) *types.Header
 	BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) *types.Block
 	StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error)
+	StateAndHeaderByHash(ctx context.Context, blockHash common.Hash) (*state.StateDB, *types.Header, error)
+	OldestStateNumber() uint64
 	GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error)
 	GetReceipts(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error)
 	GetDetailTxsLog(ctx context.Context, hash common.Hash) ([]*types.DetailTx, error)
 	GetBlockDetailLog(ctx context.Context, blockNr rpc.BlockNumber) *types.BlockAndResult
 	GetTd(blockHash common.Hash) *big.Int
 	GetEVM(ctx context.Context, account *accountmanager.AccountManager, state *state.StateDB, from common.Name, to common.Name, assetID uint64, gasPrice *big.Int, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error)
-	GetDetailTxByFilter(ctx context.Context, filterFn func(common.Name) bool, blockNr, lookbackNum uint64) []*types.DetailTx
-	GetTxsByFilter(ctx context.Context, filterFn func(common.Name) bool, blockNr, lookbackNum uint64) *types.AccountTxs
-	GetBadBlocks(ctx context.Context) ([]*types.Block, error)
+	CallTimeout() time.Duration
+	EstimateGasTimeout() time.Duration
+	MaxLookback() uint64
+	ReplayTransaction(ctx context.Context, block *types.Block, txIndex int, vmCfg vm.Config) (*types.Receipt, error)
+	GetDetailTxByFilter(ctx context.Context, filterFn func(common.Name) bool, blockNr, lookbackNum, offset, limit uint64) ([]*types.DetailTx, bool, error)
+	GetTxsByFilter(ctx context.Context, filterFn func(common.Name) bool, bloomFn func(types.Bloom) bool, blockNr, lookbackNum uint64) (*types.AccountTxs, error)
+	GetBadBlocks(ctx context.Context) ([]*blockchain.BadBlock, error)
+	GetBadBlock(ctx context.Context, hash common.Hash) *blockchain.BadBlock
+	RemoveBadBlock(ctx context.Context, hash common.Hash) bool
+	ReprocessBlock(ctx context.Context, block *types.Block, vmCfg vm.Config) ([]*types.Receipt, error)
 	ForkStatus(statedb *state.StateDB) (*blockchain.ForkConfig, blockchain.ForkInfo, error)
 	SetStatePruning(enable bool) (bool, uint64)
 
 	// TxPool
 	TxPool() *txpool.TxPool
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	GetPoolTransactions() ([]*types.Transaction, error)
+	TxPoolStats() (pending int, queued int)
+	TxPoolContent() (map[common.Name][]*types.Transaction, map[common.Name][]*types.Transaction)
 
 	SetGasPrice(gasPrice *big.Int) bool
 
@@ -146,6 +159,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   debug.Handler,
 		},
+		{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPrivateDebugAPI(apiBackend),
+		},
 	}
 	return append(apis, apiBackend.APIs()...)
 }
@@ -0,0 +1,159 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package rpcapi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/fractalplatform/fractal/accountmanager"
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/params"
+	"github.com/fractalplatform/fractal/rawdb"
+	"github.com/fractalplatform/fractal/rpc"
+	"github.com/fractalplatform/fractal/state"
+	"github.com/fractalplatform/fractal/types"
+)
+
+// testBackend is a Backend over an in memory chain, every block shares one state.
+// Methods not overridden here panic through the nil embedded Backend.
+type testBackend struct {
+	Backend
+	blocks      []*types.Block
+	state       *state.StateDB
+	maxLookback uint64
+
+	detailTxArgs []uint64 // blockNr, lookbackNum, offset and limit of the last GetDetailTxByFilter
+	pool         []*types.Transaction
+
+	txsFilterArgs [][2]uint64 // blockNr and lookforwardNum of every GetTxsByFilter
+}
+
+// newTestBackend creates a chain of n+1 blocks, block i holds i transactions.
+func newTestBackend(t *testing.T, n int) *testBackend {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &testBackend{state: statedb, maxLookback: 1000}
+	var parent common.Hash
+	for i := 0; i <= n; i++ {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(i)),
+			Time:       big.NewInt(int64(i)),
+			GasLimit:   params.BlockGasLimit,
+			Difficulty: big.NewInt(1),
+		}
+		txs := make([]*types.Transaction, i)
+		for j := range txs {
+			action := types.NewAction(types.Transfer, common.Name("testsender"), common.Name("testreceiver"), uint64(j), 0, 0, big.NewInt(0), nil, nil)
+			txs[j] = types.NewTransaction(0, big.NewInt(int64(j+1)), action)
+		}
+		block := types.NewBlockWithHeader(header).WithBody(txs)
+		b.blocks = append(b.blocks, block)
+		parent = block.Hash()
+	}
+	return b
+}
+
+// newTestAccount creates the account in the state of the backend.
+func (b *testBackend) newTestAccount(t *testing.T, name common.Name) *accountmanager.AccountManager {
+	am, err := accountmanager.NewAccountManager(b.state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := am.CreateAccount(common.Name("fractal.founder"), name, common.Name(""), 0, 0, common.PubKey{}, ""); err != nil {
+		t.Fatal(err)
+	}
+	return am
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig { return params.DefaultChainconfig }
+
+func (b *testBackend) CurrentBlock() *types.Block { return b.blocks[len(b.blocks)-1] }
+
+func (b *testBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) *types.Block {
+	if blockNr == rpc.LatestBlockNumber {
+		return b.CurrentBlock()
+	}
+	if blockNr < 0 || int(blockNr) >= len(b.blocks) {
+		return nil
+	}
+	return b.blocks[blockNr]
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) *types.Header {
+	if block := b.BlockByNumber(ctx, blockNr); block != nil {
+		return block.Header()
+	}
+	return nil
+}
+
+func (b *testBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	header := b.HeaderByNumber(ctx, blockNr)
+	if header == nil {
+		return nil, nil, nil
+	}
+	return b.state, header, nil
+}
+
+func (b *testBackend) OldestStateNumber() uint64 { return 0 }
+
+func (b *testBackend) GetTd(blockHash common.Hash) *big.Int { return big.NewInt(1) }
+
+func (b *testBackend) MaxLookback() uint64 { return b.maxLookback }
+
+func (b *testBackend) GetPoolTransactions() ([]*types.Transaction, error) { return b.pool, nil }
+
+// GetDetailTxByFilter checks the lookback like the node does and returns an empty
+// page, the arguments are kept in detailTxArgs.
+func (b *testBackend) GetDetailTxByFilter(ctx context.Context, filterFn func(common.Name) bool, blockNr, lookbackNum, offset, limit uint64) ([]*types.DetailTx, bool, error) {
+	if lookbackNum > b.maxLookback {
+		return nil, false, fmt.Errorf("lookbackNum %d exceeds the limit %d, please page the request", lookbackNum, b.maxLookback)
+	}
+	b.detailTxArgs = []uint64{blockNr, lookbackNum, offset, limit}
+	return nil, false, nil
+}
+
+func (b *testBackend) GetAccountManager() (*accountmanager.AccountManager, error) {
+	return accountmanager.NewAccountManager(b.state)
+}
+
+// GetTxsByFilter checks the lookforward like the node does and scans every block,
+// the ranges asked for are kept in txsFilterArgs.
+func (b *testBackend) GetTxsByFilter(ctx context.Context, filterFn func(common.Name) bool, bloomFn func(types.Bloom) bool, blockNr, lookforwardNum uint64) (*types.AccountTxs, error) {
+	if lookforwardNum > b.maxLookback {
+		return nil, fmt.Errorf("lookforwardNum %d exceeds the limit %d, please page the request", lookforwardNum, b.maxLookback)
+	}
+	b.txsFilterArgs = append(b.txsFilterArgs, [2]uint64{blockNr, lookforwardNum})
+	accountTxs := &types.AccountTxs{}
+	for number := blockNr; number <= blockNr+lookforwardNum && number < uint64(len(b.blocks)); number++ {
+		for i, tx := range b.blocks[number].Transactions() {
+			for _, action := range tx.GetActions() {
+				if filterFn(action.Sender()) || filterFn(action.Recipient()) {
+					accountTxs.Txs = append(accountTxs.Txs, &types.TxHeightHashPair{Hash: tx.Hash(), Height: number, Index: uint64(i)})
+					break
+				}
+			}
+		}
+	}
+	accountTxs.EndHeight = blockNr + lookforwardNum
+	return accountTxs, nil
+}
@@ -21,18 +21,27 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/fractalplatform/fractal/accountmanager"
+	"github.com/fractalplatform/fractal/blockchain"
 	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/event"
 	"github.com/fractalplatform/fractal/params"
 	"github.com/fractalplatform/fractal/processor"
 	"github.com/fractalplatform/fractal/processor/vm"
 	"github.com/fractalplatform/fractal/rawdb"
 	"github.com/fractalplatform/fractal/rpc"
+	"github.com/fractalplatform/fractal/state"
+	"github.com/fractalplatform/fractal/txpool"
 	"github.com/fractalplatform/fractal/types"
+	"github.com/fractalplatform/fractal/utils/rlp"
 )
 
 // PublicBlockChainAPI provides an API to access the blockchain.
@@ -47,13 +56,15 @@ func NewPublicBlockChainAPI(b Backend) *PublicBlockChainAPI {
 }
 
 // GetCurrentBlock returns current block.
-func (s *PublicBlockChainAPI) GetCurrentBlock(fullTx bool) map[string]interface{} {
-	return s.rpcOutputBlock(s.b.ChainConfig().ChainID, s.b.CurrentBlock(), true, fullTx)
+func (s *PublicBlockChainAPI) GetCurrentBlock(fullTx bool) (result map[string]interface{}, err error) {
+	defer timeMethod("getCurrentBlock", &err)()
+	return s.rpcOutputBlock(s.b.ChainConfig().ChainID, s.b.CurrentBlock(), true, fullTx), nil
 }
 
 // GetBlockByHash returns the requested block. When fullTx is true all transactions in the block are returned in full
 // detail, otherwise only the transaction hash is returned.
-func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool) (map[string]interface{}, error) {
+func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool) (result map[string]interface{}, err error) {
+	defer timeMethod("getBlockByHash", &err)()
 	block, err := s.b.GetBlock(ctx, blockHash)
 	if block != nil {
 		return s.rpcOutputBlock(s.b.ChainConfig().ChainID, block, true, fullTx), nil
@@ -63,13 +74,243 @@ func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash comm
 
 // GetBlockByNumber returns the requested block. When blockNr is -1 the chain head is returned. When fullTx is true all
 // transactions in the block are returned in full detail, otherwise only the transaction hash is returned.
-func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) map[string]interface{} {
+func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (result map[string]interface{}, err error) {
+	defer timeMethod("getBlockByNumber", &err)()
 	block := s.b.BlockByNumber(ctx, blockNr)
 	if block != nil {
 		response := s.rpcOutputBlock(s.b.ChainConfig().ChainID, block, true, fullTx)
-		return response
+		return response, nil
 	}
-	return nil
+	return nil, nil
+}
+
+// maxBlockRange is the maximum number of blocks that a single range query may span.
+const maxBlockRange = 256
+
+// resolveBlockNumber converts the latest and pending block number placeholders into
+// the current height, the node doesn't execute a pending block.
+func (s *PublicBlockChainAPI) resolveBlockNumber(blockNr rpc.BlockNumber) uint64 {
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		return s.b.CurrentBlock().NumberU64()
+	}
+	return uint64(blockNr)
+}
+
+// checkBlockRange resolves start and end to block heights and checks that they form a valid range.
+func (s *PublicBlockChainAPI) checkBlockRange(start, end rpc.BlockNumber) (uint64, uint64, error) {
+	from, to := s.resolveBlockNumber(start), s.resolveBlockNumber(end)
+	if from > to {
+		return 0, 0, fmt.Errorf("invalid block range: start %d is greater than end %d", from, to)
+	}
+	if to-from+1 > maxBlockRange {
+		return 0, 0, fmt.Errorf("block range too large: %d blocks requested, max %d", to-from+1, maxBlockRange)
+	}
+	return from, to, nil
+}
+
+// GetBlocksByNumber returns the blocks from start to end in ascending order. Heights that
+// don't exist are skipped. When fullTx is true all transactions in the blocks are returned
+// in full detail, otherwise only the transaction hashes are returned.
+func (s *PublicBlockChainAPI) GetBlocksByNumber(ctx context.Context, start rpc.BlockNumber, end rpc.BlockNumber, fullTx bool) (result []map[string]interface{}, err error) {
+	defer timeMethod("getBlocksByNumber", &err)()
+	from, to, err := s.checkBlockRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]map[string]interface{}, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		block := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil {
+			continue
+		}
+		blocks = append(blocks, s.rpcOutputBlock(s.b.ChainConfig().ChainID, block, true, fullTx))
+	}
+	return blocks, nil
+}
+
+// UtilizationPoint is the gas utilization of a single block.
+type UtilizationPoint struct {
+	BlockNumber uint64  `json:"blockNumber"`
+	GasUsed     uint64  `json:"gasUsed"`
+	GasLimit    uint64  `json:"gasLimit"`
+	Ratio       float64 `json:"ratio"`
+}
+
+// GetUtilizationHistory returns the gas utilization of each block from start to end in
+// ascending order. Only headers are read, heights that don't exist are skipped.
+func (s *PublicBlockChainAPI) GetUtilizationHistory(ctx context.Context, start, end rpc.BlockNumber) (result []UtilizationPoint, err error) {
+	defer timeMethod("getUtilizationHistory", &err)()
+	from, to, err := s.checkBlockRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]UtilizationPoint, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		header := s.b.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if header == nil {
+			continue
+		}
+		point := UtilizationPoint{
+			BlockNumber: number,
+			GasUsed:     header.GasUsed,
+			GasLimit:    header.GasLimit,
+		}
+		if header.GasLimit > 0 {
+			point.Ratio = float64(header.GasUsed) / float64(header.GasLimit)
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// GetBlockGasPrices returns the gas price of each transaction included in the given block,
+// in the order of the transactions.
+func (s *PublicBlockChainAPI) GetBlockGasPrices(ctx context.Context, blockNr rpc.BlockNumber) (result []*big.Int, err error) {
+	defer timeMethod("getBlockGasPrices", &err)()
+	block := s.b.BlockByNumber(ctx, blockNr)
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNr)
+	}
+	txs := block.Transactions()
+	prices := make([]*big.Int, len(txs))
+	for i, tx := range txs {
+		prices[i] = tx.GasPrice()
+	}
+	return prices, nil
+}
+
+// maxTopTransactions is the maximum number of transactions GetTopTransactions returns.
+const maxTopTransactions = 100
+
+// GetTopTransactions returns at most limit transactions from start to end with the
+// largest total action amount ("value") or total action gas limit ("gas").
+func (s *PublicBlockChainAPI) GetTopTransactions(ctx context.Context, start, end rpc.BlockNumber, by string, limit uint64) (result []*types.RPCTransaction, err error) {
+	defer timeMethod("getTopTransactions", &err)()
+	var weight func(tx *types.Transaction) *big.Int
+	switch by {
+	case "value":
+		weight = func(tx *types.Transaction) *big.Int {
+			sum := new(big.Int)
+			for _, action := range tx.GetActions() {
+				sum.Add(sum, action.Value())
+			}
+			return sum
+		}
+	case "gas":
+		weight = func(tx *types.Transaction) *big.Int {
+			sum := new(big.Int)
+			for _, action := range tx.GetActions() {
+				sum.Add(sum, new(big.Int).SetUint64(action.Gas()))
+			}
+			return sum
+		}
+	default:
+		return nil, fmt.Errorf("invalid sort key %q, want \"value\" or \"gas\"", by)
+	}
+	if limit == 0 || limit > maxTopTransactions {
+		return nil, fmt.Errorf("invalid limit %d, must be between 1 and %d", limit, maxTopTransactions)
+	}
+	from, to, err := s.checkBlockRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type weightedTx struct {
+		tx     *types.RPCTransaction
+		weight *big.Int
+	}
+	var txs []weightedTx
+	for number := from; number <= to; number++ {
+		block := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil {
+			continue
+		}
+		for index, tx := range block.Transactions() {
+			txs = append(txs, weightedTx{
+				tx:     tx.NewRPCTransaction(block.Hash(), block.NumberU64(), uint64(index)),
+				weight: weight(tx),
+			})
+		}
+	}
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].weight.Cmp(txs[j].weight) > 0 })
+	if uint64(len(txs)) > limit {
+		txs = txs[:limit]
+	}
+	result = make([]*types.RPCTransaction, len(txs))
+	for i, tx := range txs {
+		result[i] = tx.tx
+	}
+	return result, nil
+}
+
+const (
+	// maxExportLimit is the maximum number of transactions in one export page.
+	maxExportLimit = 1000
+	// maxExportScan is the maximum number of blocks scanned for one export page.
+	maxExportScan = 10000
+)
+
+// ExportPage is a page of transactions returned by ExportTransactions.
+type ExportPage struct {
+	Transactions []*types.RPCTransaction `json:"transactions"`
+	Cursor       string                  `json:"cursor"` // position to resume the export from
+	Done         bool                    `json:"done"`   // reached the current block
+}
+
+// parseExportCursor decodes a cursor of the form "<block>:<index>".
+func parseExportCursor(cursor string) (uint64, uint64, error) {
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	number, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor %q: %v", cursor, err)
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor %q: %v", cursor, err)
+	}
+	return number, index, nil
+}
+
+// ExportTransactions returns up to limit transactions in chain order starting at
+// fromBlock, or at cursor when it is not empty. The returned cursor encodes the block
+// and transaction index following the last exported transaction, so an interrupted
+// export can be resumed with it and repeated calls return the same pages.
+func (s *PublicBlockChainAPI) ExportTransactions(ctx context.Context, fromBlock uint64, cursor string, limit uint64) (result *ExportPage, err error) {
+	defer timeMethod("exportTransactions", &err)()
+	if limit == 0 || limit > maxExportLimit {
+		return nil, fmt.Errorf("invalid limit %d, must be between 1 and %d", limit, maxExportLimit)
+	}
+	number, index := fromBlock, uint64(0)
+	if cursor != "" {
+		var err error
+		if number, index, err = parseExportCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	page := &ExportPage{Transactions: make([]*types.RPCTransaction, 0, limit)}
+	current := s.b.CurrentBlock().NumberU64()
+	for scanned := 0; number <= current && scanned < maxExportScan; scanned++ {
+		block := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		txs := block.Transactions()
+		for ; index < uint64(len(txs)); index++ {
+			if uint64(len(page.Transactions)) == limit {
+				page.Cursor = fmt.Sprintf("%d:%d", number, index)
+				return page, nil
+			}
+			page.Transactions = append(page.Transactions, txs[index].NewRPCTransaction(block.Hash(), number, index))
+		}
+		number, index = number+1, 0
+	}
+	page.Cursor = fmt.Sprintf("%d:%d", number, index)
+	page.Done = number > current
+	return page, nil
 }
 
 // rpcOutputBlock uses the generalized output filler, then adds the total difficulty field, which requires
@@ -80,13 +321,14 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(chainID *big.Int, b *types.Block, i
 	return fields
 }
 
-func (s *PublicBlockChainAPI) GetBlockByNumberWithPayer(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) map[string]interface{} {
+func (s *PublicBlockChainAPI) GetBlockByNumberWithPayer(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (result map[string]interface{}, err error) {
+	defer timeMethod("getBlockByNumberWithPayer", &err)()
 	block := s.b.BlockByNumber(ctx, blockNr)
 	if block != nil {
 		response := s.rpcOutputBlockWithPayer(s.b.ChainConfig().ChainID, block, true, fullTx)
-		return response
+		return response, nil
 	}
-	return nil
+	return nil, nil
 }
 
 func (s *PublicBlockChainAPI) rpcOutputBlockWithPayer(chainID *big.Int, b *types.Block, inclTx bool, fullTx bool) map[string]interface{} {
@@ -95,23 +337,265 @@ func (s *PublicBlockChainAPI) rpcOutputBlockWithPayer(chainID *big.Int, b *types
 	return fields
 }
 
+// NewCanonicalHeads sends a notification with the block fields, without transactions,
+// each time a new block becomes the canonical head. When the new head replaces blocks
+// of the previous one, the number of replaced blocks is reported as reorgDepth.
+func (s *PublicBlockChainAPI) NewCanonicalHeads(ctx context.Context) (result *rpc.Subscription, err error) {
+	defer timeMethod("newCanonicalHeads", &err)()
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		ch := make(chan *event.Event, 10)
+		sub := event.Subscribe(nil, ch, event.ChainHeadEv, &types.Block{})
+		defer sub.Unsubscribe()
+
+		var last *types.Header
+		for {
+			select {
+			case ev := <-ch:
+				block := ev.Data.(*types.Block)
+				// notify every canonical head once
+				if last != nil && last.Hash() == block.Hash() {
+					continue
+				}
+				fields := s.rpcOutputBlock(s.b.ChainConfig().ChainID, block, false, false)
+				if depth := s.reorgDepth(last, block.Header()); depth > 0 {
+					fields["reorgDepth"] = depth
+				}
+				last = block.Header()
+				notifier.Notify(rpcSub.ID, fields)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// ChainReorg sends a notification each time the canonical chain switches to another
+// branch, listing the dropped and the added block hashes.
+func (s *PublicBlockChainAPI) ChainReorg(ctx context.Context) (result *rpc.Subscription, err error) {
+	defer timeMethod("chainReorg", &err)()
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		ch := make(chan *event.Event, 10)
+		sub := event.Subscribe(nil, ch, event.ChainReorgEv, &blockchain.ChainReorgEvent{})
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-ch:
+				notifier.Notify(rpcSub.ID, ev.Data.(*blockchain.ChainReorgEvent))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// reorgDepth returns the number of blocks of the old head's chain that are not
+// ancestors of the new head.
+func (s *PublicBlockChainAPI) reorgDepth(oldHead, newHead *types.Header) uint64 {
+	if oldHead == nil || newHead.ParentHash == oldHead.Hash() {
+		return 0
+	}
+	var depth uint64
+	oldh, newh := oldHead, newHead
+	for oldh != nil && newh != nil && oldh.Hash() != newh.Hash() {
+		if oldh.Number.Uint64() >= newh.Number.Uint64() {
+			oldh = rawdb.ReadHeader(s.b.ChainDb(), oldh.ParentHash, oldh.Number.Uint64()-1)
+			depth++
+		} else {
+			newh = rawdb.ReadHeader(s.b.ChainDb(), newh.ParentHash, newh.Number.Uint64()-1)
+		}
+	}
+	return depth
+}
+
 // GetTransactionByHash returns the transaction for the given hash
-func (s *PublicBlockChainAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) *types.RPCTransaction {
+func (s *PublicBlockChainAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (result *types.RPCTransaction, err error) {
+	defer timeMethod("getTransactionByHash", &err)()
 	// Try to return an already finalized transaction
 	if tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash); tx != nil {
-		return tx.NewRPCTransaction(blockHash, blockNumber, index)
+		return tx.NewRPCTransaction(blockHash, blockNumber, index), nil
 	}
 	// No finalized transaction, try to retrieve it from the pool
 	if tx := s.b.TxPool().Get(hash); tx != nil {
-		return tx.NewRPCTransaction(common.Hash{}, 0, 0)
+		return tx.NewRPCTransaction(common.Hash{}, 0, 0), nil
 	}
 
 	// Transaction unknown, return as such
-	return nil
+	return nil, nil
+}
+
+// GetPendingTransactions returns the executable transactions of the pool. If the
+// optional name is given, only the transactions sent by that account are returned.
+func (s *PublicBlockChainAPI) GetPendingTransactions(ctx context.Context, name *common.Name) (result []*types.RPCTransaction, err error) {
+	defer timeMethod("getPendingTransactions", &err)()
+	pending, err := s.b.GetPoolTransactions()
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]*types.RPCTransaction, 0, len(pending))
+	for _, tx := range pending {
+		if name != nil && tx.GetActions()[0].Sender() != *name {
+			continue
+		}
+		txs = append(txs, tx.NewRPCTransaction(common.Hash{}, 0, 0))
+	}
+	return txs, nil
+}
+
+// GetTxPoolStatus returns the number of pending and queued transactions in the pool.
+func (s *PublicBlockChainAPI) GetTxPoolStatus(ctx context.Context) (result map[string]hexutil.Uint, err error) {
+	defer timeMethod("getTxPoolStatus", &err)()
+	pending, queued := s.b.TxPoolStats()
+	return map[string]hexutil.Uint{
+		"pending": hexutil.Uint(pending),
+		"queued":  hexutil.Uint(queued),
+	}, nil
+}
+
+// GetTxPoolContent returns the pending and queued transactions of the pool,
+// grouped by sender account name and nonce.
+func (s *PublicBlockChainAPI) GetTxPoolContent(ctx context.Context) (result map[string]map[string]map[string]*types.RPCTransaction, err error) {
+	defer timeMethod("getTxPoolContent", &err)()
+	flatten := func(m map[common.Name][]*types.Transaction) map[string]map[string]*types.RPCTransaction {
+		dump := make(map[string]map[string]*types.RPCTransaction)
+		for name, txs := range m {
+			byNonce := make(map[string]*types.RPCTransaction)
+			for _, tx := range txs {
+				byNonce[fmt.Sprintf("%d", tx.GetActions()[0].Nonce())] = tx.NewRPCTransaction(common.Hash{}, 0, 0)
+			}
+			dump[name.String()] = byNonce
+		}
+		return dump
+	}
+	pending, queued := s.b.TxPoolContent()
+	return map[string]map[string]map[string]*types.RPCTransaction{
+		"pending": flatten(pending),
+		"queued":  flatten(queued),
+	}, nil
+}
+
+// PoolPosition is the position of a transaction in the pool queue of its sender.
+type PoolPosition struct {
+	Hash         common.Hash `json:"hash"`
+	Account      common.Name `json:"account"`
+	Nonce        uint64      `json:"nonce"`
+	Executable   bool        `json:"executable"`   // false if blocked by a nonce gap
+	Position     uint64      `json:"position"`     // index in the pending or queued list of the account
+	PendingNonce uint64      `json:"pendingNonce"` // next nonce the pool expects from the account
+}
+
+// GetTransactionPoolPosition returns the position of a pool transaction in the queue of its sender,
+// or nil if the transaction is not in the pool.
+func (s *PublicBlockChainAPI) GetTransactionPoolPosition(ctx context.Context, hash common.Hash) (result *PoolPosition, err error) {
+	defer timeMethod("getTransactionPoolPosition", &err)()
+	pool := s.b.TxPool()
+	tx := pool.Get(hash)
+	if tx == nil {
+		return nil, nil
+	}
+	action := tx.GetActions()[0]
+	pendingNonce, err := pool.State().GetNonce(action.Sender())
+	if err != nil {
+		return nil, err
+	}
+	position := &PoolPosition{
+		Hash:         hash,
+		Account:      action.Sender(),
+		Nonce:        action.Nonce(),
+		PendingNonce: pendingNonce,
+	}
+
+	pending, queued := pool.ContentFrom(action.Sender())
+	for i, ptx := range pending {
+		if ptx.Hash() == hash {
+			position.Executable = true
+			position.Position = uint64(i)
+			return position, nil
+		}
+	}
+	for i, qtx := range queued {
+		if qtx.Hash() == hash {
+			position.Position = uint64(i)
+			return position, nil
+		}
+	}
+	// The transaction left the pool in the meantime
+	return nil, nil
+}
+
+// GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
+func (s *PublicBlockChainAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr rpc.BlockNumber) (result *hexutil.Uint, err error) {
+	defer timeMethod("getBlockTransactionCountByNumber", &err)()
+	if block := s.b.BlockByNumber(ctx, blockNr); block != nil {
+		n := hexutil.Uint(len(block.Transactions()))
+		return &n, nil
+	}
+	return nil, nil
+}
+
+// GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash.
+func (s *PublicBlockChainAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash common.Hash) (result *hexutil.Uint, err error) {
+	defer timeMethod("getBlockTransactionCountByHash", &err)()
+	if block, _ := s.b.GetBlock(ctx, blockHash); block != nil {
+		n := hexutil.Uint(len(block.Transactions()))
+		return &n, nil
+	}
+	return nil, nil
+}
+
+// GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
+func (s *PublicBlockChainAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) (result *types.RPCTransaction, err error) {
+	defer timeMethod("getTransactionByBlockNumberAndIndex", &err)()
+	if block := s.b.BlockByNumber(ctx, blockNr); block != nil {
+		return newRPCTransactionFromBlockIndex(block, uint64(index)), nil
+	}
+	return nil, nil
+}
+
+// GetTransactionByBlockHashAndIndex returns the transaction for the given block hash and index.
+func (s *PublicBlockChainAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) (result *types.RPCTransaction, err error) {
+	defer timeMethod("getTransactionByBlockHashAndIndex", &err)()
+	if block, _ := s.b.GetBlock(ctx, blockHash); block != nil {
+		return newRPCTransactionFromBlockIndex(block, uint64(index)), nil
+	}
+	return nil, nil
+}
+
+// newRPCTransactionFromBlockIndex returns the transaction at the given index of the block, or nil if out of range.
+func newRPCTransactionFromBlockIndex(b *types.Block, index uint64) *types.RPCTransaction {
+	txs := b.Transactions()
+	if index >= uint64(len(txs)) {
+		return nil
+	}
+	return txs[index].NewRPCTransaction(b.Hash(), b.NumberU64(), index)
 }
 
-func (s *PublicBlockChainAPI) GetTransactions(ctx context.Context, hashes []common.Hash) []*types.RPCTransaction {
-	var result []*types.RPCTransaction
+func (s *PublicBlockChainAPI) GetTransactions(ctx context.Context, hashes []common.Hash) (result []*types.RPCTransaction, err error) {
+	defer timeMethod("getTransactions", &err)()
 	for i, hash := range hashes {
 		if i > 2048 {
 			break
@@ -120,11 +604,12 @@ func (s *PublicBlockChainAPI) GetTransactions(ctx context.Context, hashes []comm
 			result = append(result, tx.NewRPCTransaction(blockHash, blockNumber, index))
 		}
 	}
-	return result
+	return result, nil
 }
 
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
-func (s *PublicBlockChainAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.RPCReceipt, error) {
+func (s *PublicBlockChainAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (result *types.RPCReceipt, err error) {
+	defer timeMethod("getTransactionReceipt", &err)()
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
 	if tx == nil {
 		return nil, nil
@@ -141,7 +626,33 @@ func (s *PublicBlockChainAPI) GetTransactionReceipt(ctx context.Context, hash co
 	return receipt.NewRPCReceipt(blockHash, blockNumber, index, tx), nil
 }
 
-func (s *PublicBlockChainAPI) GetTransactionReceiptWithPayer(ctx context.Context, hash common.Hash) (*types.RPCReceiptWithPayer, error) {
+// GetReceiptsByNumber returns all transaction receipts of the block with the given number.
+func (s *PublicBlockChainAPI) GetReceiptsByNumber(ctx context.Context, blockNr rpc.BlockNumber) (result []*types.RPCReceipt, err error) {
+	defer timeMethod("getReceiptsByNumber", &err)()
+	block := s.b.BlockByNumber(ctx, blockNr)
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNr)
+	}
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return []*types.RPCReceipt{}, nil
+	}
+	receipts, err := s.b.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) != len(txs) {
+		return nil, fmt.Errorf("receipts of block %d not found", block.NumberU64())
+	}
+	result = make([]*types.RPCReceipt, len(receipts))
+	for i, receipt := range receipts {
+		result[i] = receipt.NewRPCReceipt(block.Hash(), block.NumberU64(), uint64(i), txs[i])
+	}
+	return result, nil
+}
+
+func (s *PublicBlockChainAPI) GetTransactionReceiptWithPayer(ctx context.Context, hash common.Hash) (result *types.RPCReceiptWithPayer, err error) {
+	defer timeMethod("getTransactionReceiptWithPayer", &err)()
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
 	if tx == nil {
 		return nil, nil
@@ -158,24 +669,32 @@ func (s *PublicBlockChainAPI) GetTransactionReceiptWithPayer(ctx context.Context
 	return receipt.NewRPCReceiptWithPayer(blockHash, blockNumber, index, tx), nil
 }
 
-func (s *PublicBlockChainAPI) GetBlockAndResultByNumber(ctx context.Context, blockNr rpc.BlockNumber) *types.BlockAndResult {
+func (s *PublicBlockChainAPI) GetBlockAndResultByNumber(ctx context.Context, blockNr rpc.BlockNumber) (result *types.BlockAndResult, err error) {
+	defer timeMethod("getBlockAndResultByNumber", &err)()
 	r := s.b.GetBlockDetailLog(ctx, blockNr)
 	if r == nil {
-		return nil
+		return nil, nil
+	}
+	block, err := s.GetBlockByNumber(ctx, blockNr, true)
+	if err != nil {
+		return nil, err
 	}
-	block := s.GetBlockByNumber(ctx, blockNr, true)
 	r.Block = block
-	return r
+	return r, nil
 }
 
-func (s *PublicBlockChainAPI) GetBlockAndResultByNumberWithPayer(ctx context.Context, blockNr rpc.BlockNumber) *types.BlockAndResult {
+func (s *PublicBlockChainAPI) GetBlockAndResultByNumberWithPayer(ctx context.Context, blockNr rpc.BlockNumber) (result *types.BlockAndResult, err error) {
+	defer timeMethod("getBlockAndResultByNumberWithPayer", &err)()
 	r := s.b.GetBlockDetailLog(ctx, blockNr)
 	if r == nil {
-		return nil
+		return nil, nil
+	}
+	block, err := s.GetBlockByNumberWithPayer(ctx, blockNr, true)
+	if err != nil {
+		return nil, err
 	}
-	block := s.GetBlockByNumberWithPayer(ctx, blockNr, true)
 	r.Block = block
-	return r
+	return r, nil
 }
 
 // checkRangeInputArgs checks the input arguments of
@@ -190,8 +709,10 @@ func (s *PublicBlockChainAPI) checkRangeInputArgs(blockNr, lookbackNum uint64) e
 
 // GetTxsByAccount return all txs, sent from or received by a specific account
 // the range is indicate by blockNr and lookforwardNum,
-// from blocks with number from blockNr to blockNr+lookforwardNum
-func (s *PublicBlockChainAPI) GetTxsByAccount(ctx context.Context, acctName common.Name, blockNr rpc.BlockNumber, lookforwardNum uint64) (*types.AccountTxs, error) {
+// from blocks with number from blockNr to blockNr+lookforwardNum,
+// lookforwardNum must not exceed the max lookback of the node (rpc_maxlookback)
+func (s *PublicBlockChainAPI) GetTxsByAccount(ctx context.Context, acctName common.Name, blockNr rpc.BlockNumber, lookforwardNum uint64) (result *types.AccountTxs, err error) {
+	defer timeMethod("getTxsByAccount", &err)()
 	// check input arguments
 	ui64BlockNr := uint64(blockNr)
 	if err := s.checkRangeInputArgs(ui64BlockNr, lookforwardNum); err != nil {
@@ -201,15 +722,79 @@ func (s *PublicBlockChainAPI) GetTxsByAccount(ctx context.Context, acctName comm
 	filterFn := func(name common.Name) bool {
 		return name == acctName
 	}
+	bloomFn := func(bloom types.Bloom) bool {
+		return bloom.TestBytes([]byte(acctName))
+	}
+
+	return s.b.GetTxsByFilter(ctx, filterFn, bloomFn, ui64BlockNr, lookforwardNum)
+}
 
-	return s.b.GetTxsByFilter(ctx, filterFn, ui64BlockNr, lookforwardNum), nil
+// GetTxsByAccounts return hashes of all txs, sent from or received by any of the accounts
+// the range is indicate by blockNr and lookforwardNum,
+// from blocks with number from blockNr to blockNr+lookforwardNum,
+// lookforwardNum must not exceed the max lookback of the node (rpc_maxlookback)
+func (s *PublicBlockChainAPI) GetTxsByAccounts(ctx context.Context, acctNames []common.Name, blockNr rpc.BlockNumber, lookforwardNum uint64) (result []common.Hash, err error) {
+	defer timeMethod("getTxsByAccounts", &err)()
+	txs, err := s.GetTxLocatorsByAccounts(ctx, acctNames, blockNr, lookforwardNum)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]common.Hash, 0, len(txs))
+	for _, pair := range txs {
+		hashes = append(hashes, pair.Hash)
+	}
+	return hashes, nil
+}
+
+// GetTxLocatorsByAccounts is like GetTxsByAccounts, but returns the block number and the
+// index in the block along with the hash of every tx.
+func (s *PublicBlockChainAPI) GetTxLocatorsByAccounts(ctx context.Context, acctNames []common.Name, blockNr rpc.BlockNumber, lookforwardNum uint64) (result []*types.TxHeightHashPair, err error) {
+	defer timeMethod("getTxLocatorsByAccounts", &err)()
+	// check input arguments
+	ui64BlockNr := uint64(blockNr)
+	if err := s.checkRangeInputArgs(ui64BlockNr, lookforwardNum); err != nil {
+		return nil, err
+	}
+
+	names := make(map[common.Name]bool, len(acctNames))
+	for _, name := range acctNames {
+		names[name] = true
+	}
+	filterFn := func(name common.Name) bool {
+		return names[name]
+	}
+	bloomFn := func(bloom types.Bloom) bool {
+		for name := range names {
+			if bloom.TestBytes([]byte(name)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	accountTxs, err := s.b.GetTxsByFilter(ctx, filterFn, bloomFn, ui64BlockNr, lookforwardNum)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[common.Hash]bool, len(accountTxs.Txs))
+	txs := make([]*types.TxHeightHashPair, 0, len(accountTxs.Txs))
+	for _, pair := range accountTxs.Txs {
+		if seen[pair.Hash] {
+			continue
+		}
+		seen[pair.Hash] = true
+		txs = append(txs, pair)
+	}
+	return txs, nil
 }
 
 // GetTxsByBloom return all txs, filtered by a bloomByte
 // bloomByte is constructed by some quantities of account names
 // the range is indicate by blockNr and lookbackNum,
-// from blocks with number from blockNr to blockNr+lookforwardNum
-func (s *PublicBlockChainAPI) GetTxsByBloom(ctx context.Context, bloomByte hexutil.Bytes, blockNr rpc.BlockNumber, lookforwardNum uint64) (*types.AccountTxs, error) {
+// from blocks with number from blockNr to blockNr+lookforwardNum,
+// lookforwardNum must not exceed the max lookback of the node (rpc_maxlookback)
+func (s *PublicBlockChainAPI) GetTxsByBloom(ctx context.Context, bloomByte hexutil.Bytes, blockNr rpc.BlockNumber, lookforwardNum uint64) (result *types.AccountTxs, err error) {
+	defer timeMethod("getTxsByBloom", &err)()
 	// check input arguments
 	ui64BlockNr := uint64(blockNr)
 	if err := s.checkRangeInputArgs(ui64BlockNr, lookforwardNum); err != nil {
@@ -220,54 +805,131 @@ func (s *PublicBlockChainAPI) GetTxsByBloom(ctx context.Context, bloomByte hexut
 	filterFn := func(name common.Name) bool {
 		return bloom.TestBytes([]byte(name))
 	}
-	return s.b.GetTxsByFilter(ctx, filterFn, ui64BlockNr, lookforwardNum), nil
+	// a block may contain a matched name only if the blooms share bits
+	bloomFn := func(nameBloom types.Bloom) bool {
+		return new(big.Int).And(bloom.Big(), nameBloom.Big()).Sign() != 0
+	}
+	return s.b.GetTxsByFilter(ctx, filterFn, bloomFn, ui64BlockNr, lookforwardNum)
 }
 
-// GetInternalTxByAccount return all logs of internal txs, sent from or received by a specific account
-// the range is indicate by blockNr and lookbackNum,
-// from blocks with number from blockNr-lookbackNum to blockNr
-func (s *PublicBlockChainAPI) GetInternalTxByAccount(ctx context.Context, acctName common.Name, blockNr rpc.BlockNumber, lookbackNum uint64) ([]*types.DetailTx, error) {
-	// check input arguments
-	ui64BlockNr := uint64(blockNr)
-	if err := s.checkRangeInputArgs(ui64BlockNr, lookbackNum); err != nil {
+// GetAccountCounterparties returns every account which sent to or received from the
+// account from start to end, along with the number of transactions between them.
+func (s *PublicBlockChainAPI) GetAccountCounterparties(ctx context.Context, account common.Name, start, end rpc.BlockNumber) (result map[common.Name]uint64, err error) {
+	defer timeMethod("getAccountCounterparties", &err)()
+	from, to, err := s.checkBlockRange(start, end)
+	if err != nil {
 		return nil, err
 	}
-
-	if lookbackNum > 128 {
-		lookbackNum = 128
+	counterparties := make(map[common.Name]uint64)
+	for number := from; number <= to; number++ {
+		block := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil {
+			continue
+		}
+		for _, tx := range block.Transactions() {
+			// count each counterparty once per transaction
+			seen := make(map[common.Name]bool)
+			for _, action := range tx.GetActions() {
+				var counterparty common.Name
+				switch {
+				case action.Sender() == account:
+					counterparty = action.Recipient()
+				case action.Recipient() == account:
+					counterparty = action.Sender()
+				default:
+					continue
+				}
+				if counterparty == account || seen[counterparty] {
+					continue
+				}
+				seen[counterparty] = true
+				counterparties[counterparty]++
+			}
+		}
 	}
+	return counterparties, nil
+}
 
-	filterFn := func(name common.Name) bool {
-		return name == acctName
+// GetInternalTxByAccount return all logs of internal txs, sent from or received by a specific account
+// the range is indicate by blockNr and lookbackNum,
+// from blocks with number from blockNr-lookbackNum to blockNr,
+// lookbackNum must not exceed the max lookback of the node (rpc_maxlookback)
+func (s *PublicBlockChainAPI) GetInternalTxByAccount(ctx context.Context, acctName common.Name, blockNr rpc.BlockNumber, lookbackNum uint64) (result []*types.DetailTx, err error) {
+	defer timeMethod("getInternalTxByAccount", &err)()
+	page, err := s.GetInternalTxByAccountPaged(ctx, acctName, blockNr, lookbackNum, 0, 0)
+	if err != nil {
+		return nil, err
 	}
-	return s.b.GetDetailTxByFilter(ctx, filterFn, ui64BlockNr, lookbackNum), nil
+	return page.Txs, nil
 }
 
 // GetInternalTxByBloom return all logs of internal txs, filtered by a bloomByte
 // bloomByte is constructed by some quantities of account names
 // the range is indicate by blockNr and lookbackNum,
-// from blocks with number from blockNr-lookbackNum to blockNr
+// from blocks with number from blockNr-lookbackNum to blockNr,
+// lookbackNum must not exceed the max lookback of the node (rpc_maxlookback)
 func (s *PublicBlockChainAPI) GetInternalTxByBloom(ctx context.Context, bloomByte hexutil.Bytes,
-	blockNr rpc.BlockNumber, lookbackNum uint64) ([]*types.DetailTx, error) {
+	blockNr rpc.BlockNumber, lookbackNum uint64) (result []*types.DetailTx, err error) {
+	defer timeMethod("getInternalTxByBloom", &err)()
+	page, err := s.GetInternalTxByBloomPaged(ctx, bloomByte, blockNr, lookbackNum, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return page.Txs, nil
+}
+
+// InternalTxsPage is a page of internal txs, HasMore reports whether more txs match
+// after the page.
+type InternalTxsPage struct {
+	Txs     []*types.DetailTx `json:"txs"`
+	HasMore bool              `json:"hasMore"`
+}
+
+// GetInternalTxByAccountPaged is like GetInternalTxByAccount, but skips the first offset
+// matching txs and returns at most limit txs. A zero limit returns all of them.
+func (s *PublicBlockChainAPI) GetInternalTxByAccountPaged(ctx context.Context, acctName common.Name, blockNr rpc.BlockNumber, lookbackNum, offset, limit uint64) (result *InternalTxsPage, err error) {
+	defer timeMethod("getInternalTxByAccountPaged", &err)()
 	// check input arguments
 	ui64BlockNr := uint64(blockNr)
 	if err := s.checkRangeInputArgs(ui64BlockNr, lookbackNum); err != nil {
 		return nil, err
 	}
 
-	if lookbackNum > 128 {
-		lookbackNum = 128
+	filterFn := func(name common.Name) bool {
+		return name == acctName
+	}
+	txs, hasMore, err := s.b.GetDetailTxByFilter(ctx, filterFn, ui64BlockNr, lookbackNum, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &InternalTxsPage{Txs: txs, HasMore: hasMore}, nil
+}
+
+// GetInternalTxByBloomPaged is like GetInternalTxByBloom, but skips the first offset
+// matching txs and returns at most limit txs. A zero limit returns all of them.
+func (s *PublicBlockChainAPI) GetInternalTxByBloomPaged(ctx context.Context, bloomByte hexutil.Bytes,
+	blockNr rpc.BlockNumber, lookbackNum, offset, limit uint64) (result *InternalTxsPage, err error) {
+	defer timeMethod("getInternalTxByBloomPaged", &err)()
+	// check input arguments
+	ui64BlockNr := uint64(blockNr)
+	if err := s.checkRangeInputArgs(ui64BlockNr, lookbackNum); err != nil {
+		return nil, err
 	}
 
 	bloom := types.BytesToBloom(bloomByte)
 	filterFn := func(name common.Name) bool {
 		return bloom.TestBytes([]byte(name))
 	}
-	return s.b.GetDetailTxByFilter(ctx, filterFn, ui64BlockNr, lookbackNum), nil
+	txs, hasMore, err := s.b.GetDetailTxByFilter(ctx, filterFn, ui64BlockNr, lookbackNum, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &InternalTxsPage{Txs: txs, HasMore: hasMore}, nil
 }
 
 // GetInternalTxByHash return logs of internal txs include by a transcastion
-func (s *PublicBlockChainAPI) GetInternalTxByHash(ctx context.Context, hash common.Hash) (*types.DetailTx, error) {
+func (s *PublicBlockChainAPI) GetInternalTxByHash(ctx context.Context, hash common.Hash) (result *types.DetailTx, err error) {
+	defer timeMethod("getInternalTxByHash", &err)()
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
 	if tx == nil {
 		return nil, nil
@@ -281,13 +943,21 @@ func (s *PublicBlockChainAPI) GetInternalTxByHash(ctx context.Context, hash comm
 	return detailTxs[index], nil
 }
 
-func (s *PublicBlockChainAPI) GetBadBlocks(ctx context.Context, fullTx bool) ([]map[string]interface{}, error) {
+func (s *PublicBlockChainAPI) GetBadBlocks(ctx context.Context, fullTx bool) (result []map[string]interface{}, err error) {
+	defer timeMethod("getBadBlocks", &err)()
 	blocks, err := s.b.GetBadBlocks(ctx)
 	if len(blocks) != 0 {
 		badBlocks := make([]map[string]interface{}, len(blocks))
+		var wg sync.WaitGroup
 		for i, b := range blocks {
-			badBlocks[i] = s.rpcOutputBlock(s.b.ChainConfig().ChainID, b, true, fullTx)
+			wg.Add(1)
+			go func(i int, b *blockchain.BadBlock) {
+				defer wg.Done()
+				badBlocks[i] = s.rpcOutputBlock(s.b.ChainConfig().ChainID, b.Block, true, fullTx)
+				badBlocks[i]["rejectReason"] = b.Reason
+			}(i, b)
 		}
+		wg.Wait()
 		return badBlocks, nil
 	}
 	return nil, err
@@ -303,20 +973,167 @@ type CallArgs struct {
 	Value      *big.Int         `json:"value"`
 	Data       hexutil.Bytes    `json:"data"`
 	Remark     hexutil.Bytes    `json:"remark"`
+	Timeout    uint64           `json:"timeout"` // milliseconds, can only shorten the node's call timeout
+}
+
+// OverrideAccount indicates the overriding fields of an account during the execution
+// of a call. Balances are keyed by asset id and storage slots are set on top of the
+// existing contract storage.
+type OverrideAccount struct {
+	Nonce   *hexutil.Uint64             `json:"nonce"`
+	Code    *hexutil.Bytes              `json:"code"`
+	Balance map[uint64]*big.Int         `json:"balance"`
+	State   map[common.Hash]common.Hash `json:"state"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
-	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Name]OverrideAccount
 
-	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+// Apply overrides the fields of specified accounts into the given state. Accounts
+// that don't exist are created, the changes are never committed.
+func (diff *StateOverride) Apply(account *accountmanager.AccountManager, state *state.StateDB, header *types.Header) error {
+	if diff == nil {
+		return nil
+	}
+	for name, override := range *diff {
+		exist, err := account.AccountIsExist(name)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			var parent common.Name
+			if i := strings.LastIndex(name.String(), "."); i > 0 {
+				parent = common.StrToName(name.String()[:i])
+			}
+			if err := account.CreateAccount(parent, name, "", header.Number.Uint64(), header.CurForkID(), common.PubKey{}, ""); err != nil {
+				return fmt.Errorf("override account %s: %v", name, err)
+			}
+		}
+		acct, err := account.GetAccountByName(name)
+		if err != nil {
+			return err
+		}
+		if override.Nonce != nil {
+			acct.SetNonce(uint64(*override.Nonce))
+		}
+		if override.Code != nil {
+			if err := acct.SetCode(*override.Code); err != nil {
+				return fmt.Errorf("override account %s: %v", name, err)
+			}
+		}
+		for assetID, balance := range override.Balance {
+			if balance == nil || balance.Sign() < 0 {
+				return fmt.Errorf("override account %s: invalid balance of asset %d", name, assetID)
+			}
+			if _, err := acct.GetBalanceByID(assetID); err == accountmanager.ErrAccountAssetNotExist {
+				if _, err := acct.AddBalanceByID(assetID, balance); err != nil {
+					return err
+				}
+			} else if err := acct.SetBalance(assetID, balance); err != nil {
+				return err
+			}
+		}
+		if err := account.SetAccount(acct); err != nil {
+			return err
+		}
+		for key, value := range override.State {
+			state.SetState(name.String(), key, value)
+		}
+	}
+	return nil
+}
+
+// callResult is the outcome of an executed call. Err is the error the execution
+// failed with, such as vm.ErrExecutionReverted, it is nil if the call succeeded.
+type callResult struct {
+	ReturnData []byte
+	UsedGas    uint64
+	Err        error
+}
+
+// Failed reports whether the execution of the call failed.
+func (r *callResult) Failed() bool {
+	return r.Err != nil
+}
+
+// Revert returns the revert error carrying the revert reason if the execution
+// was reverted, nil otherwise.
+func (r *callResult) Revert() error {
+	if r.Err != vm.ErrExecutionReverted {
+		return nil
+	}
+	return newRevertError(r.ReturnData)
+}
+
+// doCall executes args on the state of the given block. The returned error reports
+// why the call couldn't be executed, the failure of the execution is in the result.
+func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration) (*callResult, error) {
+	defer func(start time.Time) { callRuntimeHistogram.Update(int64(time.Since(start))) }(time.Now())
+
+	state, header, err := stateAndHeaderByNumber(ctx, s.b, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return s.doCallAt(ctx, args, state, header, overrides, vmCfg, timeout)
+}
+
+// ErrStateNotAvailable is returned when the state of a known block is missing, usually
+// because the node pruned it. The state may still be served by an archive node.
+type ErrStateNotAvailable struct {
+	Block  string // number or hash of the requested block
+	Oldest uint64 // oldest block whose state is kept by the node
+}
+
+func (e *ErrStateNotAvailable) Error() string {
+	return fmt.Sprintf("state of block %s is not available, it may have been pruned (oldest available state is block %d)", e.Block, e.Oldest)
+}
+
+// stateAndHeaderByNumber returns the state and header of the given block. A missing
+// block and a block with a missing state are reported as errors. The node doesn't
+// execute a pending block, so the pending state is the state of the latest block.
+func stateAndHeaderByNumber(ctx context.Context, b Backend, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	if blockNr == rpc.PendingBlockNumber {
+		blockNr = rpc.LatestBlockNumber
+	}
+	state, header, err := b.StateAndHeaderByNumber(ctx, blockNr)
+	if header == nil {
+		return nil, nil, fmt.Errorf("block %d not found", blockNr)
+	}
+	if state == nil || err != nil {
+		log.Debug("State not available", "number", header.Number, "err", err)
+		return nil, nil, &ErrStateNotAvailable{Block: header.Number.String(), Oldest: b.OldestStateNumber()}
+	}
+	return state, header, nil
+}
+
+// stateAndHeaderByHash returns the state and header of the block with the given hash,
+// which doesn't need to be canonical.
+func stateAndHeaderByHash(ctx context.Context, b Backend, blockHash common.Hash) (*state.StateDB, *types.Header, error) {
+	state, header, err := b.StateAndHeaderByHash(ctx, blockHash)
+	if header == nil {
+		return nil, nil, fmt.Errorf("block %x not found", blockHash)
+	}
 	if state == nil || err != nil {
-		return nil, 0, false, err
+		log.Debug("State not available", "hash", blockHash, "err", err)
+		return nil, nil, &ErrStateNotAvailable{Block: blockHash.Hex(), Oldest: b.OldestStateNumber()}
 	}
+	return state, header, nil
+}
+
+func (s *PublicBlockChainAPI) doCallAt(ctx context.Context, args CallArgs, state *state.StateDB, header *types.Header, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration) (*callResult, error) {
 	account, err := accountmanager.NewAccountManager(state)
 	if err != nil {
-		return nil, 0, false, err
+		return nil, err
+	}
+	if err := overrides.Apply(account, state, header); err != nil {
+		return nil, err
 	}
+	return s.applyCall(ctx, account, state, header, args, vmCfg, timeout)
+}
 
+// applyCall executes the action of args on top of the given state. The state
+// changes are kept, so that subsequent calls on the same state observe them.
+func (s *PublicBlockChainAPI) applyCall(ctx context.Context, account *accountmanager.AccountManager, state *state.StateDB, header *types.Header, args CallArgs, vmCfg vm.Config, timeout time.Duration) (*callResult, error) {
 	gasPrice := args.GasPrice
 	value := args.Value
 	assetID := uint64(args.AssetID)
@@ -335,7 +1152,7 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	// Get a new instance of the EVM.
 	evm, vmError, err := s.b.GetEVM(ctx, account, state, args.From, args.To, assetID, gasPrice, header, vmCfg)
 	if err != nil {
-		return nil, 0, false, err
+		return nil, err
 	}
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
@@ -348,23 +1165,127 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	// and apply the message.
 	gp := new(common.GasPool).AddGas(math.MaxUint64)
 	action := types.NewAction(args.ActionType, args.From, args.To, 0, assetID, gas, value, args.Data, args.Remark)
-	res, gas, failed, err, _ := processor.ApplyMessage(account, evm, action, gp, gasPrice, action.Sender(), assetID, s.b.ChainConfig(), s.b.Engine())
+	res, gas, _, err, vmerr := processor.ApplyMessage(account, evm, action, gp, gasPrice, action.Sender(), assetID, s.b.ChainConfig(), s.b.Engine())
 	if err := vmError(); err != nil {
-		return nil, 0, false, err
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
 	}
-	return res, gas, failed, err
+	return &callResult{ReturnData: res, UsedGas: gas, Err: vmerr}, nil
 }
 
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, _, _, err := s.doCall(ctx, args, blockNr, vm.Config{}, 5*time.Second)
-	return (hexutil.Bytes)(result), err
+// The optional overrides are applied to the state before execution and discarded afterwards.
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride) (result hexutil.Bytes, err error) {
+	defer timeMethod("call", &err)()
+	res, err := s.doCall(ctx, args, blockNr, overrides, vm.Config{}, s.callTimeout(args))
+	if err != nil {
+		return nil, err
+	}
+	return (hexutil.Bytes)(res.ReturnData), res.Revert()
+}
+
+// CallByHash is like Call, but executes on the state of the block with the given hash,
+// which doesn't need to be canonical.
+func (s *PublicBlockChainAPI) CallByHash(ctx context.Context, args CallArgs, blockHash common.Hash, overrides *StateOverride) (result hexutil.Bytes, err error) {
+	defer timeMethod("callByHash", &err)()
+	state, header, err := stateAndHeaderByHash(ctx, s.b, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.doCallAt(ctx, args, state, header, overrides, vm.Config{}, s.callTimeout(args))
+	if err != nil {
+		return nil, err
+	}
+	return (hexutil.Bytes)(res.ReturnData), res.Revert()
+}
+
+// callTimeout returns the node's call timeout, shortened by the timeout of args if set.
+func (s *PublicBlockChainAPI) callTimeout(args CallArgs) time.Duration {
+	timeout := s.b.CallTimeout()
+	if t := time.Duration(args.Timeout) * time.Millisecond; t > 0 && t < timeout {
+		timeout = t
+	}
+	return timeout
+}
+
+// CallResult is the outcome of a single call of MultiCall.
+type CallResult struct {
+	ReturnData hexutil.Bytes `json:"returnData"`
+	GasUsed    uint64        `json:"gasUsed"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// MultiCall executes the given calls in sequence on the state of the given block
+// number. All calls share one transient state, so later calls observe the effects
+// of the earlier ones. A failing call is reported in its result and doesn't abort
+// the remaining calls. Nothing is committed to the state/blockchain.
+func (s *PublicBlockChainAPI) MultiCall(ctx context.Context, args []CallArgs, blockNr rpc.BlockNumber) (result []CallResult, err error) {
+	defer timeMethod("multiCall", &err)()
+
+	state, header, err := stateAndHeaderByNumber(ctx, s.b, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	account, err := accountmanager.NewAccountManager(state)
+	if err != nil {
+		return nil, err
+	}
+
+	// The node's call timeout bounds the whole batch.
+	ctx, cancel := context.WithTimeout(ctx, s.b.CallTimeout())
+	defer cancel()
+
+	results := make([]CallResult, len(args))
+	for i, arg := range args {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("multi call aborted at call %d: %v", i, err)
+		}
+		timeout := time.Duration(arg.Timeout) * time.Millisecond
+		res, err := s.applyCall(ctx, account, state, header, arg, vm.Config{}, timeout)
+		switch {
+		case err != nil:
+			results[i].Error = err.Error()
+		case res.Failed():
+			results[i] = CallResult{ReturnData: res.ReturnData, GasUsed: res.UsedGas, Error: res.Err.Error()}
+			if revert := res.Revert(); revert != nil {
+				results[i].Error = revert.Error()
+			}
+		default:
+			results[i] = CallResult{ReturnData: res.ReturnData, GasUsed: res.UsedGas}
+		}
+	}
+	return results, nil
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (uint64, error) {
+// given transaction against the current pending block. The optional gasBuffer
+// inflates the estimate by the given percentage, capped at the gas allowance.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, gasBuffer *hexutil.Uint64) (result uint64, err error) {
+	defer timeMethod("estimateGas", &err)()
+	state, header, err := stateAndHeaderByNumber(ctx, s.b, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	return s.estimateGas(ctx, args, state, header, gasBuffer)
+}
+
+// EstimateGasByHash is like EstimateGas, but executes on the state of the block with
+// the given hash, which doesn't need to be canonical.
+func (s *PublicBlockChainAPI) EstimateGasByHash(ctx context.Context, args CallArgs, blockHash common.Hash, gasBuffer *hexutil.Uint64) (result uint64, err error) {
+	defer timeMethod("estimateGasByHash", &err)()
+	state, header, err := stateAndHeaderByHash(ctx, s.b, blockHash)
+	if err != nil {
+		return 0, err
+	}
+	return s.estimateGas(ctx, args, state, header, gasBuffer)
+}
+
+// estimateGas binary searches the gas requirement of args on the given state. Every
+// execution is reverted before the next one.
+func (s *PublicBlockChainAPI) estimateGas(ctx context.Context, args CallArgs, state *state.StateDB, header *types.Header, gasBuffer *hexutil.Uint64) (uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.GasTableInstance.ActionGas - 1
@@ -374,25 +1295,50 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (u
 	if uint64(args.Gas) >= params.GasTableInstance.ActionGas {
 		hi = uint64(args.Gas)
 	} else {
-		// Retrieve the current pending block to act as the gas ceiling
-		block := s.b.BlockByNumber(ctx, rpc.LatestBlockNumber)
-		hi = block.GasLimit()
+		// The block gas limit acts as the gas ceiling
+		hi = header.GasLimit
 	}
 	cap = hi
 
+	// Bound the whole search, every execution inherits the deadline
+	ctx, cancel := context.WithTimeout(ctx, s.b.EstimateGasTimeout())
+	defer cancel()
+
+	account, err := accountmanager.NewAccountManager(state)
+	if err != nil {
+		return 0, err
+	}
+
+	// No allowance below the intrinsic gas of the action can succeed
+	action := types.NewAction(args.ActionType, args.From, args.To, 0, args.AssetID, 0, args.Value, args.Data, args.Remark)
+	if intrinsic, err := txpool.IntrinsicGas(account, action); err == nil && intrinsic > lo+1 {
+		if intrinsic > hi {
+			return 0, fmt.Errorf("intrinsic gas %d exceeds allowance %d", intrinsic, hi)
+		}
+		lo = intrinsic - 1
+	}
+
 	// Create a helper to check if a gas allowance results in an executable transaction
-	executable := func(gas uint64) bool {
+	executable := func(gas uint64) (bool, error) {
+		snapshot := state.Snapshot()
+		defer state.RevertToSnapshot(snapshot)
 		args.Gas = gas
-		_, _, failed, err := s.doCall(ctx, args, rpc.LatestBlockNumber, vm.Config{}, 0)
-		if err != nil || failed {
-			return false
+		res, err := s.applyCall(ctx, account, state, header, args, vm.Config{}, 0)
+		if err != nil {
+			return false, nil
 		}
-		return true
+		if res.Failed() {
+			return false, res.Revert()
+		}
+		return true, nil
 	}
 	// Execute the binary search and hone in on an executable gas limit
 	for lo+1 < hi {
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("gas estimation aborted: %v", err)
+		}
 		mid := (hi + lo) / 2
-		if !executable(mid) {
+		if ok, _ := executable(mid); !ok {
 			lo = mid
 		} else {
 			hi = mid
@@ -400,17 +1346,509 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (u
 	}
 	// Reject the transaction as invalid if it still fails at the highest allowance
 	if hi == cap {
-		if !executable(hi) {
+		if ok, err := executable(hi); !ok {
+			if err != nil {
+				return 0, err
+			}
 			return 0, fmt.Errorf("gas required exceeds allowance or always failing transaction")
 		}
 	}
+	if gasBuffer != nil {
+		percent := new(big.Int).Add(new(big.Int).SetUint64(uint64(*gasBuffer)), big.NewInt(100))
+		buffered := new(big.Int).Mul(new(big.Int).SetUint64(hi), percent)
+		buffered.Div(buffered, big.NewInt(100))
+		if !buffered.IsUint64() || buffered.Uint64() > cap {
+			return cap, nil
+		}
+		return buffered.Uint64(), nil
+	}
 	return hi, nil
 }
 
+// EstimateAccountCreationCost returns the intrinsic gas charged for creating the named
+// account with the given initial authors. The first public key author is set by the
+// create account action itself, the remaining authors require an additional update
+// account author action whose gas is included in the estimate.
+func (s *PublicBlockChainAPI) EstimateAccountCreationCost(ctx context.Context, name common.Name, authors []*common.Author) (result hexutil.Uint64, err error) {
+	defer timeMethod("estimateAccountCreationCost", &err)()
+	am, err := s.b.GetAccountManager()
+	if err != nil {
+		return 0, err
+	}
+
+	acctManagerName := common.StrToName(s.b.ChainConfig().AccountName)
+	createAcct := &accountmanager.CreateAccountAction{AccountName: name}
+	authorActions := make([]*accountmanager.AuthorAction, 0, len(authors))
+	for _, author := range authors {
+		if author == nil || author.Owner == nil {
+			return 0, fmt.Errorf("invalid author: empty owner")
+		}
+		owner := author.Owner.String()
+		if common.IsHexPubKey(owner) {
+			pubkey := common.HexToPubKey(owner)
+			if createAcct.PublicKey == (common.PubKey{}) {
+				createAcct.PublicKey = pubkey
+				continue
+			}
+			author = common.NewAuthor(pubkey, author.Weight)
+		}
+		authorActions = append(authorActions, &accountmanager.AuthorAction{ActionType: accountmanager.AddAuthor, Author: author})
+	}
+
+	payload, err := rlp.EncodeToBytes(createAcct)
+	if err != nil {
+		return 0, err
+	}
+	action := types.NewAction(types.CreateAccount, name, acctManagerName, 0, 0, 0, big.NewInt(0), payload, nil)
+	gas, err := txpool.IntrinsicGas(am, action)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(authorActions) > 0 {
+		payload, err := rlp.EncodeToBytes(&accountmanager.AccountAuthorAction{AuthorActions: authorActions})
+		if err != nil {
+			return 0, err
+		}
+		action := types.NewAction(types.UpdateAccountAuthor, name, acctManagerName, 0, 0, 0, big.NewInt(0), payload, nil)
+		authorGas, err := txpool.IntrinsicGas(am, action)
+		if err != nil {
+			return 0, err
+		}
+		gas += authorGas
+	}
+	return hexutil.Uint64(gas), nil
+}
+
+// GetOldestStateNumber returns the number of the oldest block whose state is kept by the
+// node. Calls and state reads on older blocks may fail with ErrStateNotAvailable.
+func (s *PublicBlockChainAPI) GetOldestStateNumber(ctx context.Context) (result uint64, err error) {
+	defer timeMethod("getOldestStateNumber", &err)()
+	return s.b.OldestStateNumber(), nil
+}
+
+// accountManagerByNumber returns the account manager built on the state of the given block.
+func (s *PublicBlockChainAPI) accountManagerByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*accountmanager.AccountManager, *state.StateDB, error) {
+	state, _, err := stateAndHeaderByNumber(ctx, s.b, blockNr)
+	if err != nil {
+		return nil, nil, err
+	}
+	am, err := accountmanager.NewAccountManager(state)
+	if err != nil {
+		return nil, nil, err
+	}
+	return am, state, nil
+}
+
+// GetAccountNonce returns the nonce of the account at the given block. For the
+// pending block the nonce includes the transactions of the account in txpool.
+func (s *PublicBlockChainAPI) GetAccountNonce(ctx context.Context, accountName common.Name, blockNr rpc.BlockNumber) (result uint64, err error) {
+	defer timeMethod("getAccountNonce", &err)()
+	if blockNr == rpc.PendingBlockNumber {
+		return s.b.TxPool().State().GetNonce(accountName)
+	}
+	am, _, err := s.accountManagerByNumber(ctx, blockNr)
+	if err != nil {
+		return 0, err
+	}
+	return am.GetNonce(accountName)
+}
+
+// GetAccountBalances returns all non-zero asset balances of the account at the given block.
+func (s *PublicBlockChainAPI) GetAccountBalances(ctx context.Context, accountName common.Name, blockNr rpc.BlockNumber) (result []*accountmanager.AccountBalance, err error) {
+	defer timeMethod("getAccountBalances", &err)()
+	am, _, err := s.accountManagerByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return am.GetAllAccountBalances(accountName)
+}
+
+// maxBalanceSamples is the maximum number of blocks sampled by a balance history query.
+const maxBalanceSamples = 512
+
+// BalancePoint is the balance of an account at a single block.
+type BalancePoint struct {
+	BlockNumber uint64   `json:"blockNumber"`
+	Balance     *big.Int `json:"balance"`
+}
+
+// GetBalanceHistory returns the balance of the asset held by the account at every step-th
+// block from start to end. Blocks before the account was created are skipped.
+func (s *PublicBlockChainAPI) GetBalanceHistory(ctx context.Context, accountName common.Name, assetID uint64, start, end rpc.BlockNumber, step uint64) (result []BalancePoint, err error) {
+	defer timeMethod("getBalanceHistory", &err)()
+	if step == 0 {
+		return nil, fmt.Errorf("invalid step %d: must be greater than zero", step)
+	}
+	from, to := s.resolveBlockNumber(start), s.resolveBlockNumber(end)
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: start %d is greater than end %d", from, to)
+	}
+	if samples := (to-from)/step + 1; samples > maxBalanceSamples {
+		return nil, fmt.Errorf("too many samples: %d requested, max %d", samples, maxBalanceSamples)
+	}
+	points := make([]BalancePoint, 0)
+	for number := from; number <= to; number += step {
+		am, _, err := s.accountManagerByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		acct, err := am.GetAccountByName(accountName)
+		if err != nil {
+			return nil, err
+		}
+		if acct != nil {
+			balance, err := acct.GetBalanceByID(assetID)
+			if err == accountmanager.ErrAccountAssetNotExist {
+				balance, err = big.NewInt(0), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, BalancePoint{BlockNumber: number, Balance: balance})
+		}
+		if to-number < step {
+			break
+		}
+	}
+	return points, nil
+}
+
+// StorageProof is the merkle proof of a contract storage slot.
+type StorageProof struct {
+	Key   common.Hash     `json:"key"`
+	Value common.Hash     `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// AccountProof is the merkle proof of an account and of some of its storage slots. All
+// account data lives in one state trie, so every proof is verified against StateRoot.
+type AccountProof struct {
+	Name         common.Name                      `json:"name"`
+	Nonce        uint64                           `json:"nonce"`
+	Balances     []*accountmanager.AccountBalance `json:"balances"`
+	CodeHash     common.Hash                      `json:"codeHash"`
+	StateRoot    common.Hash                      `json:"stateRoot"`
+	AccountProof []hexutil.Bytes                  `json:"accountProof"`
+	StorageProof []StorageProof                   `json:"storageProof"`
+}
+
+func toHexSlice(proof [][]byte) []hexutil.Bytes {
+	nodes := make([]hexutil.Bytes, len(proof))
+	for i, node := range proof {
+		nodes[i] = node
+	}
+	return nodes
+}
+
+// GetProof returns the merkle proof of the account and of the given storage keys at the
+// given block. For a missing account the proof nodes prove its absence.
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, accountName common.Name, storageKeys []common.Hash, blockNr rpc.BlockNumber) (result *AccountProof, err error) {
+	defer timeMethod("getProof", &err)()
+	state, header, err := stateAndHeaderByNumber(ctx, s.b, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	am, err := accountmanager.NewAccountManager(state)
+	if err != nil {
+		return nil, err
+	}
+	accountProof, err := am.GetAccountProof(accountName)
+	if err != nil {
+		return nil, err
+	}
+	result = &AccountProof{
+		Name:         accountName,
+		Balances:     make([]*accountmanager.AccountBalance, 0),
+		StateRoot:    header.Root,
+		AccountProof: toHexSlice(accountProof),
+		StorageProof: make([]StorageProof, len(storageKeys)),
+	}
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	if acct != nil {
+		result.Nonce = acct.GetNonce()
+		if codeHash, err := acct.GetCodeHash(); err == nil {
+			result.CodeHash = codeHash
+		}
+		if result.Balances, err = am.GetAllAccountBalances(accountName); err != nil {
+			return nil, err
+		}
+	}
+	for i, key := range storageKeys {
+		proof, err := state.GetStorageProof(accountName.String(), key)
+		if err != nil {
+			return nil, err
+		}
+		result.StorageProof[i] = StorageProof{
+			Key:   key,
+			Value: state.GetState(accountName.String(), key),
+			Proof: toHexSlice(proof),
+		}
+	}
+	return result, nil
+}
+
+// AccountDump is the state of a single account.
+type AccountDump struct {
+	Name      common.Name                      `json:"name"`
+	AccountID uint64                           `json:"accountID"`
+	Nonce     uint64                           `json:"nonce"`
+	Balances  []*accountmanager.AccountBalance `json:"balances"`
+	Code      hexutil.Bytes                    `json:"code,omitempty"`
+	CodeHash  common.Hash                      `json:"codeHash"`
+}
+
+// StateDump is the state of all accounts at a block.
+type StateDump struct {
+	Number   uint64         `json:"number"`
+	Root     common.Hash    `json:"root"`
+	Accounts []*AccountDump `json:"accounts"`
+}
+
+func dumpAccount(am *accountmanager.AccountManager, acct *accountmanager.Account) (*AccountDump, error) {
+	dump := &AccountDump{
+		Name:      acct.GetName(),
+		AccountID: acct.GetAccountID(),
+		Nonce:     acct.GetNonce(),
+	}
+	balances, err := am.GetAllAccountBalances(acct.GetName())
+	if err != nil {
+		return nil, err
+	}
+	dump.Balances = balances
+	if acct.HaveCode() {
+		if dump.Code, err = acct.GetCode(); err != nil {
+			return nil, err
+		}
+		if dump.CodeHash, err = acct.GetCodeHash(); err != nil {
+			return nil, err
+		}
+	}
+	return dump, nil
+}
+
+// DumpAccount returns the state of the account at the given block.
+func (s *PublicBlockChainAPI) DumpAccount(ctx context.Context, accountName common.Name, blockNr rpc.BlockNumber) (result *AccountDump, err error) {
+	defer timeMethod("dumpAccount", &err)()
+	am, _, err := s.accountManagerByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	if acct == nil {
+		return nil, accountmanager.ErrAccountNotExist
+	}
+	return dumpAccount(am, acct)
+}
+
+// AccountInfo summarizes an account at a block.
+type AccountInfo struct {
+	Name                  common.Name                      `json:"name"`
+	Nonce                 uint64                           `json:"nonce"`
+	AuthorVersion         common.Hash                      `json:"authorVersion"`
+	Threshold             uint64                           `json:"threshold"`
+	UpdateAuthorThreshold uint64                           `json:"updateAuthorThreshold"`
+	IsContract            bool                             `json:"isContract"`
+	Balances              []*accountmanager.AccountBalance `json:"balances"`
+}
+
+// GetAccountInfo returns the nonce, author set, code status and non-zero balances
+// of the account at the given block.
+func (s *PublicBlockChainAPI) GetAccountInfo(ctx context.Context, accountName common.Name, blockNr rpc.BlockNumber) (result *AccountInfo, err error) {
+	defer timeMethod("getAccountInfo", &err)()
+	am, _, err := s.accountManagerByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	acct, err := am.GetAccountByName(accountName)
+	if err != nil {
+		return nil, err
+	}
+	if acct == nil {
+		return nil, accountmanager.ErrAccountNotExist
+	}
+	balances, err := am.GetAllAccountBalances(accountName)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountInfo{
+		Name:                  acct.GetName(),
+		Nonce:                 acct.GetNonce(),
+		AuthorVersion:         acct.GetAuthorVersion(),
+		Threshold:             acct.GetThreshold(),
+		UpdateAuthorThreshold: acct.GetUpdateAuthorThreshold(),
+		IsContract:            acct.HaveCode(),
+		Balances:              balances,
+	}, nil
+}
+
+// GetStorageAt returns the value of the storage slot of the contract account at the given block.
+// An unset slot returns 32 zero bytes.
+func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, account common.Name, key common.Hash, blockNr rpc.BlockNumber) (result hexutil.Bytes, err error) {
+	defer timeMethod("getStorageAt", &err)()
+	am, state, err := s.accountManagerByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	haveCode, err := am.AccountHaveCode(account)
+	if err != nil {
+		return nil, err
+	}
+	if !haveCode {
+		return nil, fmt.Errorf("account %s has no contract code", account)
+	}
+	value := state.GetState(account.String(), key)
+	return value[:], nil
+}
+
+// GetCode returns the contract code of the account at the given block, an account
+// without code returns an empty slice.
+func (s *PublicBlockChainAPI) GetCode(ctx context.Context, account common.Name, blockNr rpc.BlockNumber) (result hexutil.Bytes, err error) {
+	defer timeMethod("getCode", &err)()
+	am, _, err := s.accountManagerByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	code, err := am.GetCode(account)
+	switch err {
+	case nil:
+		return code, nil
+	case accountmanager.ErrCodeIsEmpty:
+		return hexutil.Bytes{}, nil
+	case accountmanager.ErrAccountNotExist:
+		return nil, fmt.Errorf("account %s not exist at block %d", account, blockNr)
+	default:
+		return nil, err
+	}
+}
+
+// maxActivityScan is the maximum number of blocks scanned for the first activity of an account.
+const maxActivityScan = 10000
+
+// FirstActivity is the first block an account sent and received a transaction in.
+type FirstActivity struct {
+	Account       common.Name `json:"account"`
+	CreatedBlock  uint64      `json:"createdBlock"`
+	FirstSent     *uint64     `json:"firstSent"`
+	FirstReceived *uint64     `json:"firstReceived"`
+	ScannedTo     uint64      `json:"scannedTo"` // last block scanned
+	// Truncated is set when the scan stopped at maxActivityScan before the head, a null
+	// field is then not found within the lookback rather than never happened.
+	Truncated bool `json:"truncated"`
+}
+
+// GetAccountFirstActivity returns the first block the account was a sender and the first it was a
+// recipient of an action, scanning at most maxActivityScan blocks forward from its creation. Blocks
+// are skipped by the name bloom index, the scan is paged by the max lookback of the node.
+func (s *PublicBlockChainAPI) GetAccountFirstActivity(ctx context.Context, account common.Name) (result *FirstActivity, err error) {
+	defer timeMethod("getAccountFirstActivity", &err)()
+	am, err := s.b.GetAccountManager()
+	if err != nil {
+		return nil, err
+	}
+	acct, err := am.GetAccountByName(account)
+	if err != nil {
+		return nil, err
+	}
+	if acct == nil {
+		return nil, accountmanager.ErrAccountNotExist
+	}
+
+	activity := &FirstActivity{Account: account, CreatedBlock: acct.GetAccountNumber()}
+	head := s.b.CurrentBlock().NumberU64()
+	last := head
+	if end := activity.CreatedBlock + maxActivityScan - 1; end < last {
+		last = end
+	}
+	filterFn := func(name common.Name) bool {
+		return name == account
+	}
+	bloomFn := func(bloom types.Bloom) bool {
+		return bloom.TestBytes([]byte(account))
+	}
+	for from := activity.CreatedBlock; from <= last; from = activity.ScannedTo + 1 {
+		num := last - from
+		if max := s.b.MaxLookback(); num > max {
+			num = max
+		}
+		accountTxs, err := s.b.GetTxsByFilter(ctx, filterFn, bloomFn, from, num)
+		if err != nil {
+			return nil, err
+		}
+		activity.ScannedTo = from + num
+		for _, pair := range accountTxs.Txs {
+			block := s.b.BlockByNumber(ctx, rpc.BlockNumber(pair.Height))
+			if block == nil || pair.Index >= uint64(len(block.Transactions())) {
+				continue
+			}
+			for _, action := range block.Transactions()[pair.Index].GetActions() {
+				if activity.FirstSent == nil && action.Sender() == account {
+					n := pair.Height
+					activity.FirstSent = &n
+				}
+				if activity.FirstReceived == nil && action.Recipient() == account {
+					n := pair.Height
+					activity.FirstReceived = &n
+				}
+			}
+		}
+		if activity.FirstSent != nil && activity.FirstReceived != nil {
+			return activity, nil
+		}
+	}
+	activity.Truncated = last < head
+	return activity, nil
+}
+
 // GetChainConfig returns chain config.
-func (s *PublicBlockChainAPI) GetChainConfig(ctx context.Context) *params.ChainConfig {
+func (s *PublicBlockChainAPI) GetChainConfig(ctx context.Context) (result *params.ChainConfig, err error) {
+	defer timeMethod("getChainConfig", &err)()
 	g := s.b.BlockByNumber(ctx, 0)
-	return rawdb.ReadChainConfig(s.b.ChainDb(), g.Hash())
+	return rawdb.ReadChainConfig(s.b.ChainDb(), g.Hash()), nil
+}
+
+// ForkActivation fork id and the block which activated it.
+type ForkActivation struct {
+	ID     uint64 `json:"id"`
+	Name   string `json:"name"`
+	Number uint64 `json:"number"`
+	Active bool   `json:"active"`
+}
+
+// GetForkSchedule returns every known fork with its activation block number,
+// forks are activated by miner vote, so number is 0 until the fork is active.
+func (s *PublicBlockChainAPI) GetForkSchedule(ctx context.Context) (result []ForkActivation, err error) {
+	defer timeMethod("getForkSchedule", &err)()
+	current := s.b.CurrentBlock().Header()
+	curID := current.CurForkID()
+	schedule := make([]ForkActivation, 0, params.NextForkID+1)
+	for id := params.ForkID0; id <= params.NextForkID; id++ {
+		fork := ForkActivation{ID: id, Name: params.ForkNames[id], Active: id <= curID}
+		if fork.Active && id > params.ForkID0 {
+			var missing int64 = -1
+			// cur fork id of headers never decrease, search the first one reached id
+			n := sort.Search(int(current.Number.Uint64()+1), func(i int) bool {
+				header := s.b.HeaderByNumber(ctx, rpc.BlockNumber(i))
+				if header == nil {
+					missing = int64(i)
+					return true
+				}
+				return header.CurForkID() >= id
+			})
+			if missing >= 0 {
+				return nil, fmt.Errorf("block %d not found", missing)
+			}
+			fork.Number = uint64(n)
+		}
+		schedule = append(schedule, fork)
+	}
+	return schedule, nil
 }
 
 // PrivateBlockChainAPI provides an API to access the blockchain.
@@ -430,6 +1868,38 @@ func (s *PrivateBlockChainAPI) SetStatePruning(enable bool) types.BlockState {
 	return types.BlockState{PreStatePruning: prestatus, CurrentNumber: number}
 }
 
+// DumpState returns the state of every account at the given block, in order of creation.
+func (s *PrivateBlockChainAPI) DumpState(ctx context.Context, blockNr rpc.BlockNumber) (*StateDump, error) {
+	state, header, err := stateAndHeaderByNumber(ctx, s.b, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	am, err := accountmanager.NewAccountManager(state)
+	if err != nil {
+		return nil, err
+	}
+	dump := &StateDump{
+		Number:   header.Number.Uint64(),
+		Root:     header.Root,
+		Accounts: make([]*AccountDump, 0),
+	}
+	err = am.ForEachAccount(func(acct *accountmanager.Account) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		account, err := dumpAccount(am, acct)
+		if err != nil {
+			return err
+		}
+		dump.Accounts = append(dump.Accounts, account)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
+
 type RPCForkStatus struct {
 	Count            uint64 `json:"count"`
 	Percentage       uint64 `json:"percentage"`
@@ -0,0 +1,349 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package rpcapi
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/rpc"
+	"github.com/fractalplatform/fractal/types"
+)
+
+func TestGetCodeAndStorageAtPending(t *testing.T) {
+	b := newTestBackend(t, 3)
+	name := common.Name("a123456789aeee")
+	am := b.newTestAccount(t, name)
+	code := []byte{0x60, 0x01}
+	if _, err := am.SetCode(name, code); err != nil {
+		t.Fatal(err)
+	}
+	key, value := common.HexToHash("0x01"), common.HexToHash("0x02")
+	b.state.SetState(name.String(), key, value)
+
+	api := NewPublicBlockChainAPI(b)
+	ctx := context.Background()
+	for _, blockNr := range []rpc.BlockNumber{rpc.PendingBlockNumber, rpc.LatestBlockNumber, 1} {
+		got, err := api.GetCode(ctx, name, blockNr)
+		if err != nil || !bytes.Equal(got, code) {
+			t.Errorf("GetCode at %d = %x, %v, want %x", blockNr, got, err, code)
+		}
+		stored, err := api.GetStorageAt(ctx, name, key, blockNr)
+		if err != nil || !bytes.Equal(stored, value[:]) {
+			t.Errorf("GetStorageAt at %d = %x, %v, want %x", blockNr, stored, err, value)
+		}
+	}
+	if _, err := api.GetCode(ctx, name, 10); err == nil {
+		t.Error("GetCode of a missing block succeeded")
+	}
+}
+
+func TestCheckBlockRange(t *testing.T) {
+	api := NewPublicBlockChainAPI(newTestBackend(t, 10))
+	tests := []struct {
+		start, end rpc.BlockNumber
+		from, to   uint64
+		fail       bool
+	}{
+		{start: 0, end: 0, from: 0, to: 0},
+		{start: 3, end: rpc.LatestBlockNumber, from: 3, to: 10},
+		{start: 3, end: rpc.PendingBlockNumber, from: 3, to: 10},
+		{start: rpc.PendingBlockNumber, end: rpc.LatestBlockNumber, from: 10, to: 10},
+		{start: 0, end: maxBlockRange - 1, from: 0, to: maxBlockRange - 1},
+		{start: 0, end: maxBlockRange, fail: true},
+		{start: 5, end: 4, fail: true},
+		{start: rpc.LatestBlockNumber, end: 9, fail: true},
+	}
+	for i, test := range tests {
+		from, to, err := api.checkBlockRange(test.start, test.end)
+		if test.fail {
+			if err == nil {
+				t.Errorf("test %d: range %d-%d accepted", i, test.start, test.end)
+			}
+			continue
+		}
+		if err != nil || from != test.from || to != test.to {
+			t.Errorf("test %d: have %d-%d, %v, want %d-%d", i, from, to, err, test.from, test.to)
+		}
+	}
+}
+
+func TestGetBlocksByNumber(t *testing.T) {
+	api := NewPublicBlockChainAPI(newTestBackend(t, 10))
+	ctx := context.Background()
+
+	// heights beyond the head are skipped
+	blocks, err := api.GetBlocksByNumber(ctx, 8, 20, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []uint64
+	for _, block := range blocks {
+		numbers = append(numbers, block["number"].(*big.Int).Uint64())
+	}
+	if want := []uint64{8, 9, 10}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("block numbers = %v, want %v", numbers, want)
+	}
+	if _, err := api.GetBlocksByNumber(ctx, 0, maxBlockRange, false); err == nil {
+		t.Error("range wider than maxBlockRange accepted")
+	}
+	if _, err := api.GetUtilizationHistory(ctx, 2, 1); err == nil {
+		t.Error("GetUtilizationHistory accepted start > end")
+	}
+}
+
+func TestGetTopTransactions(t *testing.T) {
+	api := NewPublicBlockChainAPI(newTestBackend(t, 5))
+	ctx := context.Background()
+
+	for _, limit := range []uint64{0, maxTopTransactions + 1} {
+		if _, err := api.GetTopTransactions(ctx, 0, 5, "value", limit); err == nil {
+			t.Errorf("limit %d accepted", limit)
+		}
+	}
+	if _, err := api.GetTopTransactions(ctx, 0, 5, "nonce", 1); err == nil {
+		t.Error("invalid sort key accepted")
+	}
+	if _, err := api.GetTopTransactions(ctx, 0, maxBlockRange, "gas", 1); err == nil {
+		t.Error("range wider than maxBlockRange accepted")
+	}
+	// blocks 3 to 5 hold 12 txs
+	for limit, want := range map[uint64]int{5: 5, 12: 12, 20: 12} {
+		txs, err := api.GetTopTransactions(ctx, 3, 5, "value", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txs) != want {
+			t.Errorf("limit %d: have %d txs, want %d", limit, len(txs), want)
+		}
+	}
+}
+
+func TestExportTransactions(t *testing.T) {
+	b := newTestBackend(t, 5)
+	api := NewPublicBlockChainAPI(b)
+	ctx := context.Background()
+
+	for _, limit := range []uint64{0, maxExportLimit + 1} {
+		if _, err := api.ExportTransactions(ctx, 0, "", limit); err == nil {
+			t.Errorf("limit %d accepted", limit)
+		}
+	}
+	for _, cursor := range []string{"1", "a:0", "1:b", "1:2:3"} {
+		if _, err := api.ExportTransactions(ctx, 0, cursor, 1); err == nil {
+			t.Errorf("cursor %q accepted", cursor)
+		}
+	}
+
+	var want []common.Hash
+	for _, block := range b.blocks {
+		for _, tx := range block.Transactions() {
+			want = append(want, tx.Hash())
+		}
+	}
+	var (
+		have   []common.Hash
+		cursor string
+	)
+	for pages := 0; ; pages++ {
+		if pages > len(want) {
+			t.Fatal("export doesn't finish")
+		}
+		page, err := api.ExportTransactions(ctx, 0, cursor, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Transactions) > 4 {
+			t.Fatalf("page holds %d txs, want at most 4", len(page.Transactions))
+		}
+		for _, tx := range page.Transactions {
+			have = append(have, tx.Hash)
+		}
+		if page.Done {
+			break
+		}
+		cursor = page.Cursor
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("exported %d txs, want %d in chain order", len(have), len(want))
+	}
+
+	// the cursor takes precedence over fromBlock
+	for _, test := range []struct {
+		cursor string
+		want   common.Hash
+	}{
+		{"", b.blocks[3].Transactions()[0].Hash()},
+		{"2:1", b.blocks[2].Transactions()[1].Hash()},
+	} {
+		page, err := api.ExportTransactions(ctx, 3, test.cursor, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Transactions) != 1 || page.Transactions[0].Hash != test.want {
+			t.Errorf("cursor %q: first tx mismatch", test.cursor)
+		}
+	}
+}
+
+func TestGetBalanceHistory(t *testing.T) {
+	b := newTestBackend(t, 20)
+	name := common.Name("a123456789aeee")
+	am := b.newTestAccount(t, name)
+	if err := am.AddAccountBalanceByID(name, 0, big.NewInt(7)); err != nil {
+		t.Fatal(err)
+	}
+	api := NewPublicBlockChainAPI(b)
+	ctx := context.Background()
+
+	tests := []struct {
+		start, end rpc.BlockNumber
+		step       uint64
+		want       []uint64
+	}{
+		{0, 12, 5, []uint64{0, 5, 10}},
+		{0, 20, 5, []uint64{0, 5, 10, 15, 20}},
+		{18, rpc.LatestBlockNumber, 1, []uint64{18, 19, 20}},
+		{18, rpc.PendingBlockNumber, 1, []uint64{18, 19, 20}},
+		{7, 7, 3, []uint64{7}},
+	}
+	for i, test := range tests {
+		points, err := api.GetBalanceHistory(ctx, name, 0, test.start, test.end, test.step)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		var numbers []uint64
+		for _, point := range points {
+			if point.Balance.Cmp(big.NewInt(7)) != 0 {
+				t.Errorf("test %d: balance at %d = %v, want 7", i, point.BlockNumber, point.Balance)
+			}
+			numbers = append(numbers, point.BlockNumber)
+		}
+		if !reflect.DeepEqual(numbers, test.want) {
+			t.Errorf("test %d: sampled %v, want %v", i, numbers, test.want)
+		}
+	}
+
+	if _, err := api.GetBalanceHistory(ctx, name, 0, 0, 10, 0); err == nil {
+		t.Error("zero step accepted")
+	}
+	if _, err := api.GetBalanceHistory(ctx, name, 0, 10, 0, 1); err == nil {
+		t.Error("start > end accepted")
+	}
+	if _, err := api.GetBalanceHistory(ctx, name, 0, 0, maxBalanceSamples, 1); err == nil {
+		t.Error("more than maxBalanceSamples samples accepted")
+	}
+}
+
+func TestGetInternalTxPaged(t *testing.T) {
+	b := newTestBackend(t, 10)
+	api := NewPublicBlockChainAPI(b)
+	ctx := context.Background()
+	name := common.Name("a123456789aeee")
+
+	if _, err := api.GetInternalTxByAccountPaged(ctx, name, 11, 1, 0, 0); err == nil {
+		t.Error("blockNr beyond the head accepted")
+	}
+	if _, err := api.GetInternalTxByAccountPaged(ctx, name, 10, b.maxLookback+1, 0, 0); err == nil {
+		t.Error("lookbackNum beyond the max lookback accepted")
+	}
+	page, err := api.GetInternalTxByAccountPaged(ctx, name, 10, 5, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.HasMore || len(page.Txs) != 0 {
+		t.Errorf("page = %+v, want empty", page)
+	}
+	if want := []uint64{10, 5, 2, 3}; !reflect.DeepEqual(b.detailTxArgs, want) {
+		t.Errorf("backend args = %v, want %v", b.detailTxArgs, want)
+	}
+
+	bloom := types.CreateNameBloom(b.blocks[1].Transactions())
+	if _, err := api.GetInternalTxByBloomPaged(ctx, bloom.Bytes(), 11, 1, 0, 0); err == nil {
+		t.Error("blockNr beyond the head accepted")
+	}
+	if _, err := api.GetInternalTxByBloomPaged(ctx, bloom.Bytes(), 4, 4, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{4, 4, 0, 1}; !reflect.DeepEqual(b.detailTxArgs, want) {
+		t.Errorf("backend args = %v, want %v", b.detailTxArgs, want)
+	}
+}
+
+func TestGetPendingTransactions(t *testing.T) {
+	b := newTestBackend(t, 3)
+	b.pool = b.blocks[3].Transactions()
+	api := NewPublicBlockChainAPI(b)
+	ctx := context.Background()
+
+	sender, other := common.Name("testsender"), common.Name("testreceiver")
+	for _, test := range []struct {
+		name *common.Name
+		want int
+	}{
+		{nil, 3},
+		{&sender, 3},
+		{&other, 0},
+	} {
+		txs, err := api.GetPendingTransactions(ctx, test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txs) != test.want {
+			t.Errorf("name %v: have %d txs, want %d", test.name, len(txs), test.want)
+		}
+	}
+}
+
+func TestGetAccountFirstActivity(t *testing.T) {
+	b := newTestBackend(t, 5)
+	b.maxLookback = 2
+	sender, receiver := common.Name("testsender"), common.Name("testreceiver")
+	b.newTestAccount(t, sender)
+	b.newTestAccount(t, receiver)
+	api := NewPublicBlockChainAPI(b)
+	ctx := context.Background()
+
+	one := uint64(1)
+	for _, test := range []struct {
+		account common.Name
+		want    *FirstActivity
+	}{
+		{sender, &FirstActivity{Account: sender, FirstSent: &one, ScannedTo: 5}},
+		{receiver, &FirstActivity{Account: receiver, FirstReceived: &one, ScannedTo: 5}},
+	} {
+		b.txsFilterArgs = nil
+		activity, err := api.GetAccountFirstActivity(ctx, test.account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(activity, test.want) {
+			t.Errorf("%s: activity mismatch: have %+v, want %+v", test.account, activity, test.want)
+		}
+		// the scan is paged by the max lookback
+		if want := [][2]uint64{{0, 2}, {3, 2}}; !reflect.DeepEqual(b.txsFilterArgs, want) {
+			t.Errorf("%s: backend args = %v, want %v", test.account, b.txsFilterArgs, want)
+		}
+	}
+	if _, err := api.GetAccountFirstActivity(ctx, common.Name("nobody")); err == nil {
+		t.Error("expected an error for a missing account")
+	}
+}
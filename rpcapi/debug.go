@@ -0,0 +1,77 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package rpcapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/processor/vm"
+	"github.com/fractalplatform/fractal/types"
+)
+
+// PrivateDebugAPI provides private debugging methods of the blockchain.
+type PrivateDebugAPI struct {
+	b Backend
+}
+
+// NewPrivateDebugAPI creates a new debug API.
+func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
+	return &PrivateDebugAPI{b}
+}
+
+// ReprocessResult is the result of re-executing a bad block. Error is the reason
+// the block is rejected, it is empty if the block is valid now.
+type ReprocessResult struct {
+	types.BlockAndResult
+	Error string `json:"error,omitempty"`
+}
+
+// ReprocessBadBlock re-executes the bad block with the given hash on the state of its
+// parent, with the internal tx logs enabled, and returns the receipts and internal txs
+// of the execution along with the validation error. Nothing is written to the chain.
+func (api *PrivateDebugAPI) ReprocessBadBlock(ctx context.Context, blockHash common.Hash) (*ReprocessResult, error) {
+	bad := api.b.GetBadBlock(ctx, blockHash)
+	if bad == nil {
+		return nil, fmt.Errorf("bad block %x not found", blockHash)
+	}
+	receipts, err := api.b.ReprocessBlock(ctx, bad.Block, vm.Config{ContractLogFlag: true})
+	detailTxs := make([]*types.DetailTx, len(receipts))
+	for i, receipt := range receipts {
+		detailTxs[i] = receipt.GetInternalTxsLog()
+	}
+	result := &ReprocessResult{
+		BlockAndResult: types.BlockAndResult{
+			Block:     RPCMarshalBlock(api.b.ChainConfig().ChainID, bad.Block, true, true),
+			Receipts:  receipts,
+			DetailTxs: detailTxs,
+		},
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// RemoveBadBlock removes the block with the given hash from the bad-block set.
+func (api *PrivateDebugAPI) RemoveBadBlock(ctx context.Context, blockHash common.Hash) error {
+	if !api.b.RemoveBadBlock(ctx, blockHash) {
+		return fmt.Errorf("bad block %x not found", blockHash)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
@@ -34,6 +35,9 @@ var (
 	deadline = 5 * time.Minute // consider a filter inactive if it has not been polled for within deadline
 )
 
+// maxLogsBlockRange is the maximum number of blocks GetLogs scans in one call.
+const maxLogsBlockRange = 1000
+
 // filter is a helper struct that holds meta information over the filter type
 // and associated subscription in the event system.
 type filter struct {
@@ -226,6 +230,7 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+// Logs of blocks dropped by a chain reorganisation are sent again with removed set to true.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -248,7 +253,7 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 			select {
 			case logs := <-matchedLogs:
 				for _, log := range logs {
-					notifier.Notify(rpcSub.ID, &log)
+					notifier.Notify(rpcSub.ID, log.NewRPCLog())
 				}
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				logsSub.Unsubscribe()
@@ -264,7 +269,9 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 }
 
 type FilterQuery struct {
-	Accounts []common.Name // restricts matches to events created by specific contracts
+	FromBlock *big.Int      // beginning of the queried range, nil means latest block
+	ToBlock   *big.Int      // end of the range, nil means latest block
+	Accounts  []common.Name // restricts matches to events created by specific contracts
 
 	// The Topic list restricts matches to particular event topics. Each event has a list
 	// of topics. Topics matches a prefix of that list. An empty element slice matches any
@@ -319,6 +326,46 @@ func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 	return logsSub.ID, nil
 }
 
+// GetLogs returns logs matching the given argument that are stored within the state.
+func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.RPCLog, error) {
+	begin := rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+
+	// resolve latest to check the span of the range
+	from, to := begin, end
+	if from == rpc.LatestBlockNumber.Int64() || to == rpc.LatestBlockNumber.Int64() {
+		header := api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if header == nil {
+			return []*types.RPCLog{}, nil
+		}
+		if from == rpc.LatestBlockNumber.Int64() {
+			from = header.Number.Int64()
+		}
+		if to == rpc.LatestBlockNumber.Int64() {
+			to = header.Number.Int64()
+		}
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d > %d", from, to)
+	}
+	if to-from+1 > maxLogsBlockRange {
+		return nil, fmt.Errorf("block range %d exceeds limit %d", to-from+1, maxLogsBlockRange)
+	}
+
+	filter := NewRangeFilter(api.backend, from, to, crit.Accounts, crit.Topics)
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return returnLogs(logs), nil
+}
+
 // UninstallFilter removes the filter with the given filter id.
 //
 func (api *PublicFilterAPI) UninstallFilter(id rpc.ID) bool {
@@ -393,8 +440,10 @@ func returnLogs(logs []*types.Log) []*types.RPCLog {
 // UnmarshalJSON sets *args fields with given data.
 func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 	type input struct {
-		Accounts []common.Name `json:"accounts"`
-		Topics   []interface{} `json:"topics"`
+		FromBlock *rpc.BlockNumber `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber `json:"toBlock"`
+		Accounts  []common.Name    `json:"accounts"`
+		Topics    []interface{}    `json:"topics"`
 	}
 
 	var raw input
@@ -402,6 +451,13 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	if raw.FromBlock != nil {
+		args.FromBlock = big.NewInt(raw.FromBlock.Int64())
+	}
+	if raw.ToBlock != nil {
+		args.ToBlock = big.NewInt(raw.ToBlock.Int64())
+	}
+
 	args.Accounts = []common.Name{}
 	if raw.Accounts != nil {
 		for _, account := range raw.Accounts {
@@ -18,6 +18,7 @@ package filters
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/fractalplatform/fractal/common"
 	"github.com/fractalplatform/fractal/rpc"
@@ -43,7 +44,77 @@ type Filter struct {
 
 	block      common.Hash // Block hash if filtering a single block
 	begin, end int64       // Range interval if filtering multiple blocks
+}
+
+// NewRangeFilter creates a new filter which inspects the blocks to
+// figure out whether a particular block is interesting or not.
+func NewRangeFilter(backend Backend, begin, end int64, accounts []common.Name, topics [][]common.Hash) *Filter {
+	return &Filter{
+		backend:  backend,
+		db:       backend.ChainDb(),
+		accounts: accounts,
+		topics:   topics,
+		begin:    begin,
+		end:      end,
+	}
+}
+
+// Logs searches the blockchain for matching log entries, returning all from the
+// first block that contains matches.
+func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
+	header := f.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if header == nil {
+		return nil, nil
+	}
+	head := header.Number.Uint64()
 
+	if f.begin == -1 {
+		f.begin = int64(head)
+	}
+	end := uint64(f.end)
+	if f.end == -1 {
+		end = head
+	}
+
+	var logs []*types.Log
+	for number := uint64(f.begin); number <= end; number++ {
+		if err := ctx.Err(); err != nil {
+			return logs, err
+		}
+		header := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if header == nil {
+			return logs, fmt.Errorf("block %d not found", number)
+		}
+		found, err := f.blockLogs(ctx, header)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, found...)
+	}
+	return logs, nil
+}
+
+// blockLogs returns the logs matching the filter criteria within a single block.
+func (f *Filter) blockLogs(ctx context.Context, header *types.Header) ([]*types.Log, error) {
+	if !bloomFilter(header.Bloom, f.accounts, f.topics) {
+		return nil, nil
+	}
+	receipts, err := f.backend.GetReceipts(ctx, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	var unfiltered []*types.Log
+	for txIndex, receipt := range receipts {
+		for index, log := range receipt.Logs {
+			log.BlockNumber = header.Number.Uint64()
+			log.BlockHash = header.Hash()
+			log.TxHash = receipt.TxHash
+			log.TxIndex = uint(txIndex)
+			log.Index = uint(index)
+			unfiltered = append(unfiltered, log)
+		}
+	}
+	return filterLogs(unfiltered, f.accounts, f.topics), nil
 }
 
 func includes(accounts []common.Name, a common.Name) bool {
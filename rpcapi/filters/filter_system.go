@@ -255,6 +255,7 @@ func (es *EventSystem) lightFilterNewHead(newHeader *types.Header, callBack func
 	oldh := es.lastHead
 	es.lastHead = newHeader
 	if oldh == nil {
+		callBack(newHeader, false)
 		return
 	}
 	newh := newHeader
@@ -298,7 +299,7 @@ func (es *EventSystem) lightFilterLogs(header *types.Header, accounts []common.N
 		for _, logs := range logsList {
 			for _, log := range logs {
 				logcopy := *log
-				//				logcopy.Removed = remove
+				logcopy.Removed = remove
 				unfiltered = append(unfiltered, &logcopy)
 			}
 		}
@@ -313,7 +314,7 @@ func (es *EventSystem) lightFilterLogs(header *types.Header, accounts []common.N
 			for _, receipt := range receipts {
 				for _, log := range receipt.Logs {
 					logcopy := *log
-					//					logcopy.Removed = remove
+					logcopy.Removed = remove
 					unfiltered = append(unfiltered, &logcopy)
 				}
 			}
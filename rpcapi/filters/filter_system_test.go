@@ -160,6 +160,62 @@ func TestBlockSubscription(t *testing.T) {
 	<-sub1.Err()
 }
 
+// TestLightFilterNewHead tests that a chain reorganisation rolls back the blocks of the
+// old branch before the blocks of the new branch are applied.
+func TestLightFilterNewHead(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		es      = &EventSystem{backend: backend}
+	)
+	newHeader := func(parent *types.Header, extra string) *types.Header {
+		header := &types.Header{Number: big.NewInt(0), Time: big.NewInt(0), Difficulty: big.NewInt(1), Extra: []byte(extra)}
+		if parent != nil {
+			header.ParentHash = parent.Hash()
+			header.Number = new(big.Int).Add(parent.Number, big.NewInt(1))
+		}
+		rawdb.WriteHeader(db, header)
+		return header
+	}
+	genesis := newHeader(nil, "genesis")
+	a1 := newHeader(genesis, "a")
+	a2 := newHeader(a1, "a")
+	b1 := newHeader(genesis, "b")
+	b2 := newHeader(b1, "b")
+	b3 := newHeader(b2, "b")
+
+	type step struct {
+		hash   common.Hash
+		remove bool
+	}
+	var steps []step
+	record := func(header *types.Header, remove bool) {
+		steps = append(steps, step{header.Hash(), remove})
+	}
+
+	es.lightFilterNewHead(a1, record)
+	es.lightFilterNewHead(a2, record)
+	es.lightFilterNewHead(b3, record)
+
+	want := []step{
+		{a1.Hash(), false},
+		{a2.Hash(), false},
+		{a2.Hash(), true},
+		{a1.Hash(), true},
+		{b1.Hash(), false},
+		{b2.Hash(), false},
+		{b3.Hash(), false},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d", len(steps), len(want))
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("step %d: got %x removed %v, want %x removed %v", i, steps[i].hash, steps[i].remove, want[i].hash, want[i].remove)
+		}
+	}
+}
+
 // TestPendingTxFilter tests whether pending tx filters retrieve all pending transactions that are posted to the event mux.
 func TestPendingTxFilter(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,95 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/rawdb"
+	"github.com/fractalplatform/fractal/types"
+)
+
+func TestGetLogs(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		api     = NewPublicFilterAPI(backend)
+
+		account0 = common.Name("fractal.founder")
+		account1 = common.Name("supportmytest")
+		topic0   = common.HexToHash("3ac225168df54212a25c1c01fd35bebfea408fdac2e31ddd6f80a4bbf9a5f1ca")
+		topic1   = common.HexToHash("9084a792d2f8b16a62b882fd56f7860c07bf5fa91dd8a2ae7e809e5180fef0b3")
+	)
+
+	// block 2 holds a log of account0, block 4 holds a log of account1
+	var hashes []common.Hash
+	for i := int64(0); i < 5; i++ {
+		var receipts []*types.Receipt
+		switch i {
+		case 2:
+			receipts = []*types.Receipt{{TxHash: common.BytesToHash([]byte{2}), Logs: []*types.Log{{Name: account0, Topics: []common.Hash{topic0}}}}}
+		case 4:
+			receipts = []*types.Receipt{
+				{TxHash: common.BytesToHash([]byte{3})},
+				{TxHash: common.BytesToHash([]byte{4}), Logs: []*types.Log{{Name: account1, Topics: []common.Hash{topic1}}}},
+			}
+		}
+		header := &types.Header{Number: big.NewInt(i), Time: big.NewInt(i), Bloom: types.CreateBloom(receipts)}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), header.Number.Uint64())
+		rawdb.WriteReceipts(db, header.Hash(), header.Number.Uint64(), receipts)
+		rawdb.WriteHeadBlockHash(db, header.Hash())
+		hashes = append(hashes, header.Hash())
+	}
+
+	logs, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(logs))
+	}
+
+	logs, err = api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(0), Accounts: []common.Name{account1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 log, got %d", len(logs))
+	}
+	if logs[0].BlockNumber != 4 || logs[0].BlockHash != hashes[4] || logs[0].TxHash != common.BytesToHash([]byte{4}) || logs[0].TxIndex != 1 || logs[0].Index != 0 {
+		t.Fatalf("unexpected log position %+v", logs[0])
+	}
+
+	logs, err = api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(0), ToBlock: big.NewInt(3), Topics: [][]common.Hash{{topic1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 0 {
+		t.Fatalf("expected 0 logs, got %d", len(logs))
+	}
+
+	if _, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(3), ToBlock: big.NewInt(1)}); err == nil {
+		t.Fatal("expected error for invalid range")
+	}
+	if _, err := api.GetLogs(context.Background(), FilterCriteria{FromBlock: big.NewInt(0), ToBlock: big.NewInt(maxLogsBlockRange)}); err == nil {
+		t.Fatal("expected error for too large range")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package rpcapi
+
+import (
+	"time"
+
+	"github.com/fractalplatform/fractal/metrics"
+)
+
+// methodMetricsPrefix is the registry prefix of the per-method rpc timers.
+const methodMetricsPrefix = "rpc/ft/"
+
+// callRuntimeHistogram tracks the runtime of doCall in nanoseconds.
+var callRuntimeHistogram = metrics.NewRegisteredHistogram(methodMetricsPrefix+"call/runtime", nil, metrics.NewExpDecaySample(1028, 0.015))
+
+// timeMethod starts timing an rpc method, the returned func records the runtime
+// in the timer rpc/ft/<method>/success or rpc/ft/<method>/failure depending on
+// the error err points to, the timer count doubles as the call count. It is
+// meant to be deferred by rpc methods with a named error result:
+//
+//	defer timeMethod("call", &err)()
+func timeMethod(method string, err *error) func() {
+	start := time.Now()
+	return func() {
+		if !metrics.Enabled {
+			return
+		}
+		outcome := "/success"
+		if *err != nil {
+			outcome = "/failure"
+		}
+		metrics.GetOrRegisterTimer(methodMetricsPrefix+method+outcome, nil).UpdateSince(start)
+	}
+}
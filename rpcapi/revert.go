@@ -0,0 +1,60 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package rpcapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/fractalplatform/fractal/crypto"
+	"github.com/fractalplatform/fractal/utils/abi"
+)
+
+// revertSelector is the ABI selector of the standard Error(string) revert reason.
+var revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+// errExecutionReverted is returned when the revert data carries no decodable reason.
+var errExecutionReverted = errors.New("execution reverted")
+
+// unpackRevert resolves the abi-encoded revert reason. According to the solidity
+// spec, the provided revert reason is abi-encoded as if it were a call to a
+// function `Error(string)`.
+func unpackRevert(data []byte) (string, error) {
+	if len(data) < 4 || !bytes.Equal(data[:4], revertSelector) {
+		return "", errors.New("invalid revert data")
+	}
+	typ, err := abi.NewType("string")
+	if err != nil {
+		return "", err
+	}
+	var reason string
+	if err := (abi.Arguments{{Type: typ}}).Unpack(&reason, data[4:]); err != nil {
+		return "", err
+	}
+	return reason, nil
+}
+
+// newRevertError creates an error carrying the decoded revert reason, falling back
+// to a plain execution reverted error when the data is empty or not abi-encoded.
+func newRevertError(data []byte) error {
+	reason, err := unpackRevert(data)
+	if err != nil {
+		return errExecutionReverted
+	}
+	return fmt.Errorf("%v: %v", errExecutionReverted, reason)
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 The Fractal Team Authors
+// This file is part of the fractal project.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package rpcapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/processor/vm"
+	"github.com/fractalplatform/fractal/rawdb"
+	"github.com/fractalplatform/fractal/types"
+)
+
+// TraceConfig holds the options of a transaction trace.
+type TraceConfig struct {
+	DisableMemory bool `json:"disableMemory"`
+	DisableStack  bool `json:"disableStack"`
+}
+
+// StructLogRes stores a structured log emitted by the EVM while replaying a
+// transaction in debug mode.
+type StructLogRes struct {
+	Pc      uint64    `json:"pc"`
+	Op      string    `json:"op"`
+	Gas     uint64    `json:"gas"`
+	GasCost uint64    `json:"gasCost"`
+	Depth   int       `json:"depth"`
+	Error   string    `json:"error,omitempty"`
+	Stack   *[]string `json:"stack,omitempty"`
+	Memory  *[]string `json:"memory,omitempty"`
+}
+
+// ExecutionTrace is the opcode level trace of a transaction.
+type ExecutionTrace struct {
+	Gas         uint64         `json:"gas"`
+	Failed      bool           `json:"failed"`
+	Error       string         `json:"error,omitempty"`
+	ReturnValue hexutil.Bytes  `json:"returnValue"`
+	StructLogs  []StructLogRes `json:"structLogs"`
+}
+
+// formatLogs formats EVM returned structured logs for json output.
+func formatLogs(logs []vm.StructLog) []StructLogRes {
+	formatted := make([]StructLogRes, len(logs))
+	for index, trace := range logs {
+		formatted[index] = StructLogRes{
+			Pc:      trace.Pc,
+			Op:      trace.Op.String(),
+			Gas:     trace.Gas,
+			GasCost: trace.GasCost,
+			Depth:   trace.Depth,
+			Error:   trace.ErrorString(),
+		}
+		if trace.Stack != nil {
+			stack := make([]string, len(trace.Stack))
+			for i, value := range trace.Stack {
+				stack[i] = fmt.Sprintf("%x", common.LeftPadBytes(value.Bytes(), 32))
+			}
+			formatted[index].Stack = &stack
+		}
+		if trace.Memory != nil {
+			memory := make([]string, 0, (len(trace.Memory)+31)/32)
+			for i := 0; i+32 <= len(trace.Memory); i += 32 {
+				memory = append(memory, fmt.Sprintf("%x", trace.Memory[i:i+32]))
+			}
+			formatted[index].Memory = &memory
+		}
+	}
+	return formatted
+}
+
+// TraceTransaction re-executes a transaction at the state of its block with the
+// struct logger enabled, and returns the opcode level steps of the execution.
+// The optional config disables the capture of memory or stack for cheaper traces.
+func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (*ExecutionTrace, error) {
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(api.b.ChainDb(), hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %x not found", hash)
+	}
+	block, err := api.b.GetBlock(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNumber)
+	}
+
+	logConfig := &vm.LogConfig{DisableStorage: true}
+	if config != nil {
+		logConfig.DisableMemory = config.DisableMemory
+		logConfig.DisableStack = config.DisableStack
+	}
+	tracer := vm.NewStructLogger(logConfig)
+	receipt, err := api.b.ReplayTransaction(ctx, block, int(index), vm.Config{Debug: true, Tracer: tracer})
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &ExecutionTrace{
+		Gas:         receipt.TotalGasUsed,
+		ReturnValue: tracer.Output(),
+		StructLogs:  formatLogs(tracer.StructLogs()),
+	}
+	for _, result := range receipt.ActionResults {
+		if result.Status == types.ReceiptStatusFailed {
+			trace.Failed = true
+			trace.Error = result.Error
+		}
+	}
+	return trace, nil
+}
@@ -23,6 +23,7 @@ import (
 	"sync"
 
 	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/crypto"
 	"github.com/fractalplatform/fractal/rawdb"
 	"github.com/fractalplatform/fractal/types"
 	"github.com/fractalplatform/fractal/utils/fdb"
@@ -306,6 +307,33 @@ func (s *StateDB) Delete(account string, key string) {
 	s.put(optKey, nil)
 }
 
+// GetProof returns the merkle proof of the account's data stored under key. The
+// proof is made against the committed trie, uncommitted changes are not included.
+func (s *StateDB) GetProof(account string, key string) ([][]byte, error) {
+	return s.prove(acctDataPrefix + linkSymbol + account + linkSymbol + key)
+}
+
+// GetStorageProof returns the merkle proof of the contract variable key.
+func (s *StateDB) GetStorageProof(account string, key common.Hash) ([][]byte, error) {
+	return s.prove(statePrefix + linkSymbol + account + linkSymbol + key.String())
+}
+
+func (s *StateDB) prove(key string) ([][]byte, error) {
+	var proof proofList
+	if err := s.trie.Prove(crypto.Keccak256([]byte(key)), 0, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// proofList collects the encoded nodes of a merkle proof.
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
 // ReceiptRoot compute one tx‘ receipt hash
 func (s *StateDB) ReceiptRoot() common.Hash {
 	s.Finalise()
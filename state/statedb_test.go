@@ -24,7 +24,9 @@ import (
 	"time"
 
 	"github.com/fractalplatform/fractal/common"
+	"github.com/fractalplatform/fractal/crypto"
 	"github.com/fractalplatform/fractal/rawdb"
+	trie "github.com/fractalplatform/fractal/state/mtp"
 	"github.com/fractalplatform/fractal/types"
 )
 
@@ -213,6 +215,64 @@ func TestSetAndGetState(t *testing.T) {
 
 }
 
+func TestGetProof(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	batch := db.NewBatch()
+	state, _ := New(common.Hash{}, NewDatabase(db))
+	for i := 0; i < 16; i++ {
+		state.Put("acct"+strconv.Itoa(i), "data", []byte("value"+strconv.Itoa(i)))
+		state.SetState("acct"+strconv.Itoa(i), common.BytesToHash([]byte("sk")), common.BytesToHash([]byte("sv"+strconv.Itoa(i))))
+	}
+	root, err := state.Commit(batch, common.Hash{}, 0)
+	if err != nil {
+		t.Fatal("commit trie err", err)
+	}
+	triedb := state.db.TrieDB()
+	if err := triedb.Commit(root, false); err != nil {
+		t.Fatal("commit db err", err)
+	}
+	batch.Write()
+
+	state, _ = New(root, NewDatabase(db))
+	verify := func(key string, proof [][]byte) []byte {
+		proofDb := rawdb.NewMemoryDatabase()
+		for _, node := range proof {
+			proofDb.Put(crypto.Keccak256(node), node)
+		}
+		value, _, err := trie.VerifyProof(root, crypto.Keccak256([]byte(key)), proofDb)
+		if err != nil {
+			t.Fatalf("verify proof of %s: %v", key, err)
+		}
+		return value
+	}
+
+	proof, err := state.GetProof("acct3", "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := verify(acctDataPrefix+linkSymbol+"acct3"+linkSymbol+"data", proof); !bytes.Equal(value, []byte("value3")) {
+		t.Errorf("account proof value %q, want %q", value, "value3")
+	}
+
+	key := common.BytesToHash([]byte("sk"))
+	proof, err = state.GetStorageProof("acct5", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := common.BytesToHash([]byte("sv5"))
+	if value := verify(statePrefix+linkSymbol+"acct5"+linkSymbol+key.String(), proof); !bytes.Equal(value, want[:]) {
+		t.Errorf("storage proof value %x, want %x", value, want)
+	}
+
+	proof, err = state.GetProof("missing", "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := verify(acctDataPrefix+linkSymbol+"missing"+linkSymbol+"data", proof); value != nil {
+		t.Errorf("absence proof value %q, want nil", value)
+	}
+}
+
 func TestLog(t *testing.T) {
 	db := rawdb.NewMemoryDatabase()
 	cachedb := NewDatabase(db)
@@ -617,6 +617,23 @@ func (tp *TxPool) Content() (map[common.Name][]*types.Transaction, map[common.Na
 	return pending, queued
 }
 
+// ContentFrom retrieves the data content of the transaction pool, returning the
+// pending as well as queued transactions of this account, sorted by nonce.
+func (tp *TxPool) ContentFrom(name common.Name) ([]*types.Transaction, []*types.Transaction) {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+
+	var pending []*types.Transaction
+	if list, ok := tp.pending[name]; ok {
+		pending = list.Flatten()
+	}
+	var queued []*types.Transaction
+	if list, ok := tp.queue[name]; ok {
+		queued = list.Flatten()
+	}
+	return pending, queued
+}
+
 // Pending retrieves all currently processable transactions, groupped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -93,6 +93,18 @@ func (b *Bloom) UnmarshalText(input []byte) error {
 	return hexutil.UnmarshalFixedText("Bloom", input, b[:])
 }
 
+// CreateNameBloom create bloom by the sender and recipient names of txs.
+func CreateNameBloom(txs []*Transaction) Bloom {
+	bin := new(big.Int)
+	for _, tx := range txs {
+		for _, action := range tx.GetActions() {
+			bin.Or(bin, bloom9([]byte(action.Sender())))
+			bin.Or(bin, bloom9([]byte(action.Recipient())))
+		}
+	}
+	return BytesToBloom(bin.Bytes())
+}
+
 // CreateBloom create bloom by receiptes.
 func CreateBloom(receipts []*Receipt) Bloom {
 	bin := new(big.Int)
@@ -45,6 +45,7 @@ type BlockAndResult struct {
 type TxHeightHashPair struct {
 	Hash   common.Hash `json:"hash"`
 	Height uint64      `json:"height"`
+	Index  uint64      `json:"index"` // index of the transaction in the block
 }
 
 type AccountTxs struct {
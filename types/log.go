@@ -34,6 +34,9 @@ type Log struct {
 	ActionIndex uint          // index of the input and output in the transaction
 	TxIndex     uint          // index of the transaction in the block
 
+	// The Removed field is true if this log was reverted due to a chain reorganisation.
+	// It is not part of the consensus encoding.
+	Removed bool `rlp:"-"`
 }
 
 // RPCLog that will serialize to the RPC representation of a log.
@@ -47,6 +50,7 @@ type RPCLog struct {
 	Index       uint          `json:"logIndex"`
 	ActionIndex uint          `json:"actionIndex"`
 	TxIndex     uint          `json:"transactionIndex"`
+	Removed     bool          `json:"removed"`
 }
 
 // NewRPCLog returns a log that will serialize to the RPC.
@@ -61,5 +65,6 @@ func (l *Log) NewRPCLog() *RPCLog {
 		ActionIndex: l.ActionIndex,
 		BlockHash:   l.BlockHash,
 		Index:       l.Index,
+		Removed:     l.Removed,
 	}
 }